@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kishaningithub/tf-import-gen/pkg"
+)
+
+// The exit codes allow CI pipelines to tell the kinds of failures apart. The
+// codes from exitCodeUnsupportedResources to exitCodeTargetMismatches are only
+// used in strict mode and exitCodeVerificationIssues only by the verify command.
+const (
+	exitCodeError                = 1
+	exitCodeParseFailure         = 2
+	exitCodeUnsupportedResources = 3
+	exitCodeUnresolvedIDs        = 4
+	exitCodeEmptySelection       = 5
+	exitCodeTargetMismatches     = 6
+	exitCodeVerificationIssues   = 7
+)
+
+// exitError is an error which terminates tf-import-gen with a specific exit code
+type exitError struct {
+	code int
+	err  error
+}
+
+func (exitErr exitError) Error() string {
+	return exitErr.err.Error()
+}
+
+func (exitErr exitError) Unwrap() error {
+	return exitErr.err
+}
+
+func exitCode(err error) int {
+	var exitErr exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	var parseErr tfimportgen.ParseError
+	if errors.As(err, &parseErr) {
+		return exitCodeParseFailure
+	}
+	return exitCodeError
+}
+
+// checkStrict fails when the imports cannot be applied as they are, reporting
+// an empty selection first, then unresolved ids and then unsupported resources
+func checkStrict(imports tfimportgen.TerraformImports) error {
+	if len(imports) == 0 {
+		return exitError{code: exitCodeEmptySelection, err: errors.New("strict mode: no resources were selected")}
+	}
+	unresolvedIDs, unsupportedResources := 0, 0
+	for _, terraformImport := range imports {
+		if !terraformImport.SupportsImport {
+			unsupportedResources++
+		} else if len(terraformImport.Diagnostics) > 0 {
+			unresolvedIDs++
+		}
+	}
+	if unresolvedIDs > 0 {
+		return exitError{code: exitCodeUnresolvedIDs, err: fmt.Errorf("strict mode: the import ids of %d resources could not be computed", unresolvedIDs)}
+	}
+	if unsupportedResources > 0 {
+		return exitError{code: exitCodeUnsupportedResources, err: fmt.Errorf("strict mode: %d resources do not support import", unsupportedResources)}
+	}
+	return nil
+}
+
+// checkTargetMismatches fails when import targets do not match the target
+// configuration, which terraform would only report at plan time. Import targets
+// within modules which cannot be validated are only reported as warnings.
+func checkTargetMismatches(targetMismatches []tfimportgen.TargetMismatch) error {
+	mismatches := 0
+	for _, targetMismatch := range targetMismatches {
+		if targetMismatch.Kind != tfimportgen.TargetMismatchUnverifiedModule {
+			mismatches++
+		}
+	}
+	if mismatches > 0 {
+		return exitError{code: exitCodeTargetMismatches, err: fmt.Errorf("strict mode: %d import targets do not match the target configuration", mismatches)}
+	}
+	return nil
+}
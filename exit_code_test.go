@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/kishaningithub/tf-import-gen/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ExitCode(t *testing.T) {
+	_, parseErr := tfimportgen.GenerateImports(bytes.NewBufferString("{}"), nil)
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{name: "parse failure", err: parseErr, expected: exitCodeParseFailure},
+		{name: "strict mode failure", err: exitError{code: exitCodeUnresolvedIDs, err: errors.New("unresolved")}, expected: exitCodeUnresolvedIDs},
+		{name: "any other error", err: errors.New("no input"), expected: exitCodeError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, exitCode(tt.err))
+		})
+	}
+}
+
+func Test_CheckStrict(t *testing.T) {
+	supported := tfimportgen.TerraformImport{ResourceAddress: "aws_instance.a", ResourceID: "i-a", SupportsImport: true}
+	unsupported := tfimportgen.TerraformImport{ResourceAddress: "aws_iam_policy_attachment.b", ResourceID: "b"}
+	unresolved := tfimportgen.TerraformImport{
+		ResourceAddress: "aws_iam_role_policy_attachment.c",
+		SupportsImport:  true,
+		Diagnostics: []tfimportgen.Diagnostic{
+			{Kind: tfimportgen.DiagnosticMissingAttribute, Address: "aws_iam_role_policy_attachment.c", Attribute: "role"},
+		},
+	}
+	unresolvedWithIdentity := unresolved
+	unresolvedWithIdentity.Identity = map[string]any{"role": "role", "policy_arn": "arn:aws:iam::aws:policy/ReadOnlyAccess"}
+	tests := []struct {
+		name             string
+		imports          tfimportgen.TerraformImports
+		expectedExitCode int
+		expectedError    string
+	}{
+		{name: "all resources importable", imports: tfimportgen.TerraformImports{supported}},
+		{
+			name:             "no resources",
+			expectedExitCode: exitCodeEmptySelection,
+			expectedError:    "strict mode: no resources were selected",
+		},
+		{
+			name:             "unsupported resources",
+			imports:          tfimportgen.TerraformImports{supported, unsupported},
+			expectedExitCode: exitCodeUnsupportedResources,
+			expectedError:    "strict mode: 1 resources do not support import",
+		},
+		{
+			name:             "unresolved ids take precedence over unsupported resources",
+			imports:          tfimportgen.TerraformImports{unsupported, unresolved},
+			expectedExitCode: exitCodeUnresolvedIDs,
+			expectedError:    "strict mode: the import ids of 1 resources could not be computed",
+		},
+		{
+			name:    "unresolved ids of resources imported by their identity",
+			imports: tfimportgen.TerraformImports{supported, unresolvedWithIdentity}.ForFormat(tfimportgen.FormatIdentity),
+		},
+		{
+			name:             "unresolved ids of resources with an identity in other formats",
+			imports:          tfimportgen.TerraformImports{supported, unresolvedWithIdentity}.ForFormat(tfimportgen.FormatImport),
+			expectedExitCode: exitCodeUnresolvedIDs,
+			expectedError:    "strict mode: the import ids of 1 resources could not be computed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkStrict(tt.imports)
+			if len(tt.expectedError) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.expectedError)
+			require.Equal(t, tt.expectedExitCode, exitCode(err))
+		})
+	}
+}
+
+func Test_CheckTargetMismatches(t *testing.T) {
+	require.NoError(t, checkTargetMismatches(nil))
+	require.NoError(t, checkTargetMismatches([]tfimportgen.TargetMismatch{
+		{Kind: tfimportgen.TargetMismatchUnverifiedModule, Address: "module.vpc.aws_vpc.this[0]", Block: "module.vpc", Detail: "terraform-aws-modules/vpc/aws"},
+	}))
+
+	err := checkTargetMismatches([]tfimportgen.TargetMismatch{
+		{Kind: tfimportgen.TargetMismatchUnverifiedModule, Address: "module.vpc.aws_vpc.this[0]", Block: "module.vpc", Detail: "terraform-aws-modules/vpc/aws"},
+		{Kind: tfimportgen.TargetMismatchMissingResource, Address: "aws_s3_bucket.assets", Block: "aws_s3_bucket.assets"},
+	})
+
+	require.EqualError(t, err, "strict mode: 1 import targets do not match the target configuration")
+	require.Equal(t, exitCodeTargetMismatches, exitCode(err))
+}
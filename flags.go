@@ -0,0 +1,88 @@
+package main
+
+import (
+	"github.com/kishaningithub/tf-import-gen/pkg"
+	"github.com/spf13/pflag"
+)
+
+// selectionFlags are the flags deciding the inputs and the resources selected
+// from them, which every command shares so that they select the same resources
+type selectionFlags struct {
+	stateInputs       []string
+	regexAddresses    bool
+	excludedAddresses []string
+	excludedTypes     []string
+	providers         []string
+}
+
+func (flags *selectionFlags) register(flagSet *pflag.FlagSet) {
+	flagSet.StringArrayVar(&flags.stateInputs, "state", nil, "read the state from the given file instead of stdin, - reads stdin. Prefix the path with a module address like module.network=network.tfstate to import the resources into that module (can be repeated)")
+	flagSet.BoolVar(&flags.regexAddresses, "regex", false, "interpret the addresses as regular expressions which are evaluated per address segment")
+	flagSet.StringArrayVar(&flags.excludedAddresses, "exclude", nil, "exclude the resources contained in the given address, which is matched like the address arguments (can be repeated)")
+	flagSet.StringArrayVar(&flags.excludedTypes, "exclude-type", nil, "exclude the resources of the given type, which can be a glob pattern (can be repeated)")
+	flagSet.StringArrayVar(&flags.providers, "provider", nil, "only include the resources managed by the given provider, e.g. google, registry.terraform.io/hashicorp/aws or aws.us_east_1 (can be repeated)")
+}
+
+func (flags selectionFlags) options() []tfimportgen.Option {
+	options := []tfimportgen.Option{
+		tfimportgen.WithExcludedAddresses(flags.excludedAddresses...),
+		tfimportgen.WithExcludedTypes(flags.excludedTypes...),
+		tfimportgen.WithProviders(flags.providers...),
+	}
+	if flags.regexAddresses {
+		options = append(options, tfimportgen.WithRegexAddresses())
+	}
+	return options
+}
+
+// importFlags are the flags deciding the addresses the resources are imported
+// to and their import ids, which the main command shares with verify so that
+// verify checks the same imports
+type importFlags struct {
+	addressMappings     []string
+	addressMappingFiles []string
+	keyMappings         []string
+	idRulesFiles        []string
+}
+
+func (flags *importFlags) register(flagSet *pflag.FlagSet) {
+	flagSet.StringArrayVar(&flags.addressMappings, "map", nil, "rewrite addresses starting with the given address to start with another address, in the form from=to (can be repeated)")
+	flagSet.StringArrayVar(&flags.addressMappingFiles, "map-file", nil, "file with one address mapping in the form from=to per line, like the one written by the map command (can be repeated)")
+	flagSet.StringArrayVar(&flags.keyMappings, "rekey", nil, "convert count resources to for_each resources by replacing the numeric instance keys of the given resource with the value of an attribute, in the form resource=attribute (can be repeated)")
+	flagSet.StringArrayVar(&flags.idRulesFiles, "rules", nil, "json file with rules for computing the import identifiers of resource types, which take precedence over the builtin rules (can be repeated)")
+}
+
+func (flags importFlags) options() ([]tfimportgen.Option, error) {
+	var options []tfimportgen.Option
+	for _, addressMapping := range flags.addressMappings {
+		mapping, err := tfimportgen.ParseAddressMapping(addressMapping)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, tfimportgen.WithAddressMappings(mapping))
+	}
+	for _, addressMappingFile := range flags.addressMappingFiles {
+		mappings, err := loadAddressMappings(addressMappingFile)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, tfimportgen.WithAddressMappings(mappings...))
+	}
+	for _, keyMapping := range flags.keyMappings {
+		mapping, err := tfimportgen.ParseKeyMapping(keyMapping)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, tfimportgen.WithKeyMappings(mapping))
+	}
+	var idRules []tfimportgen.IDRule
+	for _, idRulesFile := range flags.idRulesFiles {
+		fileIDRules, err := loadIDRules(idRulesFile)
+		if err != nil {
+			return nil, err
+		}
+		idRules = append(idRules, fileIDRules...)
+	}
+	options = append(options, tfimportgen.WithIDRules(idRules...))
+	return options, nil
+}
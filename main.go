@@ -1,18 +1,33 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"github.com/kishaningithub/tf-import-gen/pkg"
 	"github.com/spf13/cobra"
 	"os"
+	"slices"
 	"strings"
 )
 
 var Version = "dev"
 
 func main() {
+	var plannedActions []string
+	var format string
+	var outDir string
+	var outFileName string
+	var outTopLevelOnly bool
+	var strict bool
+	var showSummary bool
+	var reportFile string
+	var providerSchemasFile string
+	var targetDir string
+	var selection selectionFlags
+	var importTargets importFlags
 	var rootCmd = &cobra.Command{
 		Use:   "tf-import-gen [flags] address...",
+		Args:  cobra.ArbitraryArgs,
 		Short: "Generate terraform import statements",
 		Long: strings.TrimSpace(`
 Generate terraform import statements to simplify state migrations from one terraform code base to another.
@@ -26,6 +41,24 @@ addresses, such as:
   module.example
   module.example.module.child
   module.example.aws_instance.example
+
+Like terraform state list, the addresses are matched on whole address
+segments, i.e. aws_instance.example does not match aws_instance.example_backup.
+Addresses without instance keys match all the instances.
+
+Module names, resource types and resource names can be glob patterns using
+* and ?, such as module.team_*.aws_iam_*.*. With --regex the addresses are
+regular expressions which are evaluated per address segment instead, such as
+module.team_.*.aws_iam_(role|policy).
+
+Errors and warnings are written to stderr. The exit codes are
+  1 for invalid flags, unreadable files and other errors
+  2 when an input is neither a state nor a plan which can be parsed
+and, with --strict,
+  3 when resources which do not support import were selected
+  4 when the import ids of resources could not be computed
+  5 when no resources were selected
+  6 when import targets do not match the configuration in --target-dir
 `),
 		Version: Version,
 		Example: `
@@ -38,24 +71,285 @@ terraform show -json | tf-import-gen aws_instance.example
 ## Generating import statements by multiple resources
 terraform show -json | tf-import-gen aws_instance.example module.example
 
+## Generating import statements for a module except a few resources
+terraform show -json | tf-import-gen module.example --exclude module.example.aws_instance.singleton --exclude-type aws_iam_policy_attachment
+
+## Generating import statements for the resources managed by a provider
+terraform state pull | tf-import-gen --provider google
+
+## Generating import statements by glob pattern
+terraform show -json | tf-import-gen 'module.team_*.aws_iam_*.*'
+
+## Generating import statements by regular expression
+terraform show -json | tf-import-gen --regex 'module.team_.*.aws_iam_(role|policy)'
+
 ## Generating import statements for all resources
 terraform show -json | tf-import-gen
+
+## Generating import statements from a state file
+terraform state pull | tf-import-gen
+
+## Generating import statements for multiple workspaces consolidated into modules of a single root configuration
+tf-import-gen --state module.network=network.tfstate --state module.app=app.tfstate
+
+## Generating one file with import statements per module
+terraform show -json | tf-import-gen --out-dir imports
+
+## Generating one import block with for_each per resource for terraform 1.7 or later
+terraform show -json | tf-import-gen --format for_each
+
+## Generating import statements using the resource identity recorded by terraform 1.12 or later
+terraform show -json | tf-import-gen --format identity
+
+## Generating import statements along with resource configuration populated from the state
+terraform show -json | tf-import-gen --format config module.example
+
+## Generating resource configuration without computed and sensitive attributes
+terraform providers schema -json > schemas.json
+terraform show -json | tf-import-gen --format config --provider-schemas schemas.json module.example
+
+## Generating import statements for resources which a plan would destroy or replace
+terraform show -json plan.tfplan | tf-import-gen --action delete,replace
+
+## Generating import statements for a module which is renamed in the destination code base
+terraform show -json | tf-import-gen --map module.old=module.new module.old
+
+## Generating import statements for count resources which are converted to for_each resources keyed by their bucket name
+terraform show -json | tf-import-gen --rekey aws_s3_bucket.this=bucket aws_s3_bucket.this
+
+## Generating import statements using the address mappings suggested by the map command
+terraform show -json | tf-import-gen map --target-dir ../destination > mappings.txt
+terraform show -json | tf-import-gen --map-file mappings.txt
+
+## Generating moved blocks for a module which is renamed within the same state
+terraform show -json | tf-import-gen --format moved --map module.old=module.new module.old
+
+## Generating removed blocks to make the source code base forget a migrated module
+terraform show -json | tf-import-gen --format removed module.example
+
+## Generating terraform import commands for terraform versions older than 1.5
+terraform show -json | tf-import-gen --format command | sh
+
+## Generating a json representation of the imports for post processing
+terraform show -json | tf-import-gen --format json
+
+## Generating import statements along with a summary for sanity checking the migration
+terraform show -json | tf-import-gen --summary --report summary.txt
+
+## Failing in CI when the generated import statements cannot be applied as they are
+terraform show -json | tf-import-gen module.example --strict
+
+## Validating the import targets against the configuration of the destination code base
+terraform show -json | tf-import-gen module.example --target-dir ../destination --strict
+
+## Generating import statements using additional rules for computing import identifiers
+terraform show -json | tf-import-gen --rules rules.json
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			// the flags are valid by now, so the usage would only hide the error
+			cmd.SilenceUsage = true
 			addresses := []string{""}
-			if len(os.Args) > 1 {
-				addresses = os.Args[1:]
+			if len(args) > 0 {
+				addresses = args
+			}
+			options := append(selection.options(), tfimportgen.WithPlannedActions(plannedActions...))
+			if tfimportgen.Format(format) == tfimportgen.FormatConfig {
+				options = append(options, tfimportgen.WithAttributeValues())
+			}
+			// the other formats contain no configuration which the schemas could prune
+			if len(providerSchemasFile) > 0 && tfimportgen.Format(format) != tfimportgen.FormatConfig {
+				return errors.New("--provider-schemas can only be used with --format config")
+			}
+			var providerSchemas tfimportgen.ProviderSchemas
+			if len(providerSchemasFile) > 0 {
+				var err error
+				providerSchemas, err = loadProviderSchemas(providerSchemasFile)
+				if err != nil {
+					return err
+				}
+				options = append(options, tfimportgen.WithProviderSchemas(providerSchemas))
+			}
+			importOptions, err := importTargets.options()
+			if err != nil {
+				return err
+			}
+			options = append(options, importOptions...)
+			inputs, err := openStateInputs(selection.stateInputs)
+			if err != nil {
+				return err
+			}
+			defer closeStateInputs(inputs)
+			// moved blocks only work within a single state, while a module prefix
+			// imports the resources of another state
+			if tfimportgen.Format(format) == tfimportgen.FormatMoved && slices.ContainsFunc(inputs, func(input tfimportgen.StateInput) bool { return len(input.ModulePrefix) > 0 }) {
+				return errors.New("format moved cannot be combined with module prefixes in --state, as moved blocks only work within a single state")
 			}
-			imports, err := tfimportgen.GenerateImports(os.Stdin, addresses)
+			imports, err := tfimportgen.GenerateImportsFromStates(inputs, addresses, options...)
 			if err != nil {
 				return err
 			}
-			fmt.Println(imports)
+			imports = imports.ForFormat(tfimportgen.Format(format))
+			for _, diagnostic := range imports.Diagnostics() {
+				fmt.Fprintf(os.Stderr, "warning: %s\n", diagnostic)
+			}
+			if len(providerSchemasFile) > 0 {
+				for _, resourceType := range providerSchemas.UnknownResourceTypes(imports) {
+					fmt.Fprintf(os.Stderr, "warning: resource type %s is not in the provider schemas, its configuration is not pruned\n", resourceType)
+				}
+			}
+			var targetMismatches []tfimportgen.TargetMismatch
+			if len(targetDir) > 0 {
+				targetConfiguration, err := tfimportgen.LoadTargetConfiguration(targetDir)
+				if err != nil {
+					return err
+				}
+				targetMismatches = targetConfiguration.Validate(imports)
+				for _, targetMismatch := range targetMismatches {
+					fmt.Fprintf(os.Stderr, "warning: %s\n", targetMismatch)
+				}
+			}
+			if len(outDir) > 0 {
+				writtenFiles, err := writeImportsToDir(imports, tfimportgen.Format(format), outDir, outFileName, outTopLevelOnly)
+				if err != nil {
+					return err
+				}
+				for _, writtenFile := range writtenFiles {
+					fmt.Println(writtenFile)
+				}
+			} else {
+				output, err := imports.Render(tfimportgen.Format(format))
+				if err != nil {
+					return err
+				}
+				fmt.Println(output)
+			}
+			summary := imports.Summary()
+			if showSummary {
+				fmt.Fprint(os.Stderr, summary)
+			}
+			if len(reportFile) > 0 {
+				if err := os.WriteFile(reportFile, []byte(summary.String()), 0o644); err != nil {
+					return err
+				}
+			}
+			if strict {
+				if err := checkTargetMismatches(targetMismatches); err != nil {
+					return err
+				}
+				return checkStrict(imports)
+			}
 			return nil
 		},
 	}
+	selection.register(rootCmd.Flags())
+	importTargets.register(rootCmd.Flags())
+	rootCmd.Flags().StringSliceVar(&plannedActions, "action", nil, "only include resources on which the plan given as input intends to take one of these actions (no-op, update, delete, replace, forget)")
+	rootCmd.Flags().StringVar(&format, "format", string(tfimportgen.FormatImport), "output format, one of import, for_each, identity, config, moved, removed, command, json")
+	rootCmd.Flags().StringVar(&outDir, "out-dir", "", "write the output into one file per module within the given directory instead of stdout")
+	rootCmd.Flags().StringVar(&outFileName, "out-file-name", defaultOutFileName, "go template for the path of the file per module within the out dir, using {{.Name}} (e.g. app.child, root for the root module), {{.Dir}} (e.g. app/child) and {{.ModulePath}} (e.g. module.app.module.child)")
+	rootCmd.Flags().BoolVar(&outTopLevelOnly, "out-top-level-only", false, "write the resources of child modules into the file of their top level module")
+	rootCmd.Flags().BoolVar(&strict, "strict", false, "fail when resources which do not support import are selected, import ids cannot be computed or no resources are selected")
+	rootCmd.Flags().BoolVar(&showSummary, "summary", false, "write a summary with the number of imports per module and resource type, the resources which do not support import and the resource types without an id rule to stderr")
+	rootCmd.Flags().StringVar(&reportFile, "report", "", "write the summary into the given file")
+	rootCmd.Flags().StringVar(&targetDir, "target-dir", "", "directory with the terraform configuration of the destination code base, against which the import targets are validated")
+	rootCmd.Flags().StringVar(&providerSchemasFile, "provider-schemas", "", "output of terraform providers schema -json, used to leave computed and sensitive attributes out of the configuration generated with --format config")
+	rootCmd.AddCommand(newMapCommand())
+	rootCmd.AddCommand(newVerifyCommand())
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.SilenceErrors = true
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(exitCode(err))
+	}
+}
+
+func loadIDRules(idRulesFile string) ([]tfimportgen.IDRule, error) {
+	file, err := os.Open(idRulesFile)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	return tfimportgen.LoadIDRules(file)
+}
+
+func loadAddressMappings(addressMappingFile string) ([]tfimportgen.AddressMapping, error) {
+	file, err := os.Open(addressMappingFile)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	mappings, err := tfimportgen.LoadAddressMappings(file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address mapping file %s: %w", addressMappingFile, err)
+	}
+	return mappings, nil
+}
+
+func loadDestinationPlan(planFile string) (tfimportgen.DestinationPlan, error) {
+	file, err := os.Open(planFile)
+	if err != nil {
+		return tfimportgen.DestinationPlan{}, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	return tfimportgen.LoadDestinationPlan(file)
+}
+
+func loadProviderSchemas(providerSchemasFile string) (tfimportgen.ProviderSchemas, error) {
+	file, err := os.Open(providerSchemasFile)
+	if err != nil {
+		return tfimportgen.ProviderSchemas{}, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	return tfimportgen.LoadProviderSchemas(file)
+}
+
+// openStateInputs opens the inputs given as [module address=]path, where the
+// path - refers to stdin. Without inputs stdin is read, unless it is a terminal.
+func openStateInputs(stateInputs []string) ([]tfimportgen.StateInput, error) {
+	if len(stateInputs) == 0 {
+		if stat, err := os.Stdin.Stat(); err == nil && stat.Mode()&os.ModeCharDevice != 0 {
+			return nil, errors.New("no input, pipe the output of terraform show -json or use --state")
+		}
+		stateInputs = []string{"-"}
+	}
+	var inputs []tfimportgen.StateInput
+	for _, stateInput := range stateInputs {
+		var input tfimportgen.StateInput
+		path := stateInput
+		if modulePrefix, modulePath, found := strings.Cut(stateInput, "="); found && strings.HasPrefix(modulePrefix, "module.") {
+			input.ModulePrefix, path = modulePrefix, modulePath
+		}
+		if path == "-" {
+			if slices.ContainsFunc(inputs, func(input tfimportgen.StateInput) bool { return input.Reader == os.Stdin }) {
+				closeStateInputs(inputs)
+				return nil, errors.New("stdin can only be read once, but - is given more than once")
+			}
+			input.Reader = os.Stdin
+		} else {
+			file, err := os.Open(path)
+			if err != nil {
+				closeStateInputs(inputs)
+				return nil, err
+			}
+			input.Reader = file
+		}
+		inputs = append(inputs, input)
+	}
+	return inputs, nil
+}
+
+func closeStateInputs(inputs []tfimportgen.StateInput) {
+	for _, input := range inputs {
+		if file, ok := input.Reader.(*os.File); ok && file != os.Stdin {
+			_ = file.Close()
+		}
 	}
 }
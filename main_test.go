@@ -0,0 +1,13 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_OpenStateInputs_ShouldRejectReadingStdinTwice(t *testing.T) {
+	_, err := openStateInputs([]string{"-", "module.network=-"})
+
+	require.EqualError(t, err, "stdin can only be read once, but - is given more than once")
+}
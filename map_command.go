@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kishaningithub/tf-import-gen/pkg"
+	"github.com/spf13/cobra"
+)
+
+func newMapCommand() *cobra.Command {
+	var targetDir string
+	var selection selectionFlags
+	mapCmd := &cobra.Command{
+		Use:   "map [flags] address...",
+		Short: "Suggest address mappings by matching the resources to the configuration of the destination code base",
+		Long: strings.TrimSpace(`
+Suggest address mappings for the resources which have no resource block in the
+configuration of the destination code base.
+
+Every resource is matched to a resource block of the same type which nothing is
+imported into yet, preferring the most similar name and module path. The
+suggestions are written as a mapping file with one mapping in the form from=to
+per line, which is meant to be reviewed and edited before it is applied with
+--map-file.
+`),
+		Example: `
+## Suggesting address mappings for a module and applying them after review
+terraform show -json | tf-import-gen map --target-dir ../destination module.example > mappings.txt
+terraform show -json | tf-import-gen --map-file mappings.txt module.example
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			addresses := []string{""}
+			if len(args) > 0 {
+				addresses = args
+			}
+			targetConfiguration, err := tfimportgen.LoadTargetConfiguration(targetDir)
+			if err != nil {
+				return err
+			}
+			inputs, err := openStateInputs(selection.stateInputs)
+			if err != nil {
+				return err
+			}
+			defer closeStateInputs(inputs)
+			for _, input := range inputs {
+				// the mappings are applied before the module prefix
+				if len(input.ModulePrefix) > 0 {
+					return errors.New("module prefixes are not supported when suggesting address mappings")
+				}
+			}
+			imports, err := tfimportgen.GenerateImportsFromStates(inputs, addresses, selection.options()...)
+			if err != nil {
+				return err
+			}
+			fmt.Print(targetConfiguration.SuggestAddressMappings(imports))
+			return nil
+		},
+	}
+	selection.register(mapCmd.Flags())
+	mapCmd.Flags().StringVar(&targetDir, "target-dir", "", "directory with the terraform configuration of the destination code base")
+	_ = mapCmd.MarkFlagRequired("target-dir")
+	return mapCmd
+}
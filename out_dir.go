@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/kishaningithub/tf-import-gen/pkg"
+)
+
+// import blocks are only allowed in the root module, hence all the files are
+// written next to each other by default
+const defaultOutFileName = "imports_{{.Name}}.tf"
+
+type outFileNameData struct {
+	// ModulePath is the path of the module without instance keys, e.g.
+	// module.app.module.child. It is empty for the root module.
+	ModulePath string
+	// Name is the module names joined by dots, e.g. app.child, or root for the
+	// root module
+	Name string
+	// Dir is the module names joined by slashes, e.g. app/child, or root for
+	// the root module
+	Dir string
+}
+
+// writeImportsToDir writes one file per module, named by the outFileName
+// template, within outDir and returns the paths of the written files
+func writeImportsToDir(imports tfimportgen.TerraformImports, format tfimportgen.Format, outDir string, outFileName string, topLevelOnly bool) ([]string, error) {
+	// the files are terraform configuration, which the other formats are not
+	if format == tfimportgen.FormatJSON || format == tfimportgen.FormatCommand {
+		return nil, fmt.Errorf("format %s cannot be written into terraform files, write it to stdout instead of --out-dir", format)
+	}
+	outFileNameTemplate, err := template.New("out-file-name").Parse(outFileName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid out file name: %w", err)
+	}
+	var writtenFiles, outputs []string
+	// writtenModules are the module paths by the path of their file, which
+	// must not be overwritten by the imports of another module
+	writtenModules := make(map[string]string)
+	for _, moduleImports := range imports.GroupByModule(topLevelOnly) {
+		fileNameData := outFileNameData{
+			ModulePath: moduleImports.ModulePath,
+			Name:       strings.Join(moduleImports.ModuleNames, "."),
+			Dir:        strings.Join(moduleImports.ModuleNames, "/"),
+		}
+		if len(moduleImports.ModuleNames) == 0 {
+			fileNameData.Name, fileNameData.Dir = "root", "root"
+		}
+		var fileName strings.Builder
+		err := outFileNameTemplate.Execute(&fileName, fileNameData)
+		if err != nil {
+			return nil, fmt.Errorf("invalid out file name: %w", err)
+		}
+		if !filepath.IsLocal(fileName.String()) {
+			return nil, fmt.Errorf("out file name %q must be a relative path within the out dir", fileName.String())
+		}
+		output, err := moduleImports.Imports.Render(format)
+		if err != nil {
+			return nil, err
+		}
+		filePath := filepath.Join(outDir, fileName.String())
+		if otherModulePath, ok := writtenModules[filePath]; ok {
+			return nil, fmt.Errorf("the imports of %s and %s would both be written to %s, the out file name must differ per module", displayModulePath(otherModulePath), displayModulePath(moduleImports.ModulePath), filePath)
+		}
+		writtenModules[filePath] = moduleImports.ModulePath
+		writtenFiles = append(writtenFiles, filePath)
+		outputs = append(outputs, output)
+	}
+	// the files are only written once all the paths are known to be distinct
+	for i, filePath := range writtenFiles {
+		err = os.MkdirAll(filepath.Dir(filePath), 0o755)
+		if err != nil {
+			return nil, err
+		}
+		err = os.WriteFile(filePath, []byte(outputs[i]), 0o644)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return writtenFiles, nil
+}
+
+func displayModulePath(modulePath string) string {
+	if len(modulePath) == 0 {
+		return "the root module"
+	}
+	return modulePath
+}
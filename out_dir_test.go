@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kishaningithub/tf-import-gen/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WriteImportsToDir(t *testing.T) {
+	outDir := t.TempDir()
+	imports := tfimportgen.TerraformImports{
+		{ResourceAddress: "aws_instance.web", ResourceID: "i-1", SupportsImport: true},
+		{ResourceAddress: `module.app["a"].module.child.aws_instance.web`, ResourceID: "i-2", SupportsImport: true},
+	}
+
+	writtenFiles, err := writeImportsToDir(imports, tfimportgen.FormatImport, outDir, defaultOutFileName, false)
+
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(outDir, "imports_root.tf"),
+		filepath.Join(outDir, "imports_app.child.tf"),
+	}, writtenFiles)
+	content, err := os.ReadFile(filepath.Join(outDir, "imports_app.child.tf"))
+	require.NoError(t, err)
+	require.Equal(t, imports[1:].String(), string(content))
+}
+
+func Test_WriteImportsToDir_ShouldRejectFileNamesOutsideOutDir(t *testing.T) {
+	imports := tfimportgen.TerraformImports{
+		{ResourceAddress: "aws_instance.web", ResourceID: "i-1", SupportsImport: true},
+	}
+
+	_, err := writeImportsToDir(imports, tfimportgen.FormatImport, t.TempDir(), "../{{.Name}}.tf", false)
+
+	require.EqualError(t, err, `out file name "../root.tf" must be a relative path within the out dir`)
+}
+
+func Test_WriteImportsToDir_ShouldRejectModulesWrittenToTheSameFile(t *testing.T) {
+	outDir := t.TempDir()
+	tests := []struct {
+		name          string
+		imports       tfimportgen.TerraformImports
+		outFileName   string
+		expectedError string
+	}{
+		{
+			name: "module named root",
+			imports: tfimportgen.TerraformImports{
+				{ResourceAddress: "aws_instance.web", ResourceID: "i-1", SupportsImport: true},
+				{ResourceAddress: "module.root.aws_instance.web", ResourceID: "i-2", SupportsImport: true},
+			},
+			outFileName:   defaultOutFileName,
+			expectedError: "the imports of the root module and module.root would both be written to " + filepath.Join(outDir, "imports_root.tf") + ", the out file name must differ per module",
+		},
+		{
+			name: "constant out file name",
+			imports: tfimportgen.TerraformImports{
+				{ResourceAddress: "module.a.aws_instance.web", ResourceID: "i-1", SupportsImport: true},
+				{ResourceAddress: "module.b.aws_instance.web", ResourceID: "i-2", SupportsImport: true},
+			},
+			outFileName:   "imports.tf",
+			expectedError: "the imports of module.a and module.b would both be written to " + filepath.Join(outDir, "imports.tf") + ", the out file name must differ per module",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := writeImportsToDir(tt.imports, tfimportgen.FormatImport, outDir, tt.outFileName, false)
+			require.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
+
+func Test_WriteImportsToDir_ShouldRejectFormatsWhichAreNotTerraformConfiguration(t *testing.T) {
+	imports := tfimportgen.TerraformImports{
+		{ResourceAddress: "aws_instance.web", ResourceID: "i-1", SupportsImport: true},
+	}
+	for _, format := range []tfimportgen.Format{tfimportgen.FormatJSON, tfimportgen.FormatCommand} {
+		t.Run(string(format), func(t *testing.T) {
+			_, err := writeImportsToDir(imports, format, t.TempDir(), defaultOutFileName, false)
+			require.EqualError(t, err, "format "+string(format)+" cannot be written into terraform files, write it to stdout instead of --out-dir")
+		})
+	}
+}
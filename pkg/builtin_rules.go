@@ -0,0 +1,95 @@
+package tfimportgen
+
+const (
+	awsSecurityGroupRuleID = `{{.security_group_id}}_{{.type}}_{{.protocol}}_{{.from_port}}_{{.to_port}}` +
+		`{{if .source_security_group_id}}_{{.source_security_group_id}}` +
+		`{{else if .cidr_blocks}}_{{join .cidr_blocks "_"}}` +
+		`{{else if .prefix_list_ids}}_{{join .prefix_list_ids "_"}}{{end}}`
+	awsRouteID = `{{.route_table_id}}_` +
+		`{{if .destination_prefix_list_id}}{{.destination_prefix_list_id}}` +
+		`{{else if .destination_cidr_block}}{{.destination_cidr_block}}` +
+		`{{else}}{{.destination_ipv6_cidr_block}}{{end}}`
+	googleConditionTitle = `{{with .condition}}{{with index . 0}} {{.title}}{{end}}{{end}}`
+)
+
+// builtinIDRules are the rules shipped with tf-import-gen. Resource types
+// without a rule are imported using their id attribute.
+var builtinIDRules = []IDRule{
+	// aws resources
+	{Type: "aws_iam_role_policy_attachment", ID: "{{.role}}/{{.policy_arn}}"},
+	{Type: "aws_cloudwatch_event_target", ID: "{{.rule}}/{{.target_id}}"},
+	{Type: "aws_lambda_permission", ID: "{{.function_name}}/{{.statement_id}}"},
+	{Type: "aws_security_group_rule", ID: awsSecurityGroupRuleID},
+	{Type: "aws_network_acl_rule", ID: "{{.network_acl_id}}:{{.rule_number}}:{{.protocol}}:{{.egress}}"},
+	{Type: "aws_api_gateway_resource", ID: "{{.rest_api_id}}/{{.id}}"},
+	{Type: "aws_api_gateway_deployment", ID: "{{.rest_api_id}}/{{.id}}"},
+	{Type: "aws_api_gateway_stage", ID: "{{.rest_api_id}}/{{.stage_name}}"},
+	{Type: "aws_api_gateway_method_settings", ID: "{{.rest_api_id}}/{{.stage_name}}/{{.method_path}}"},
+	{Type: "aws_api_gateway_method", ID: "{{.rest_api_id}}/{{.resource_id}}/{{.http_method}}"},
+	{Type: "aws_api_gateway_integration", ID: "{{.rest_api_id}}/{{.resource_id}}/{{.http_method}}"},
+	{Type: "aws_route_table_association", ID: "{{.subnet_id}}/{{.route_table_id}}"},
+	{Type: "aws_iam_user_policy_attachment", ID: "{{.user}}/{{.policy_arn}}"},
+	{Type: "aws_emr_instance_group", ID: "{{.cluster_id}}/{{.id}}"},
+	{Type: "aws_backup_selection", ID: "{{.plan_id}}|{{.id}}"},
+	{Type: "aws_vpc_endpoint_route_table_association", ID: "{{.vpc_endpoint_id}}/{{.route_table_id}}"},
+	{Type: "aws_vpc_endpoint_subnet_association", ID: "{{.vpc_endpoint_id}}/{{.subnet_id}}"},
+	{Type: "aws_cognito_user_pool_client", ID: "{{.user_pool_id}}/{{.id}}"},
+	{Type: "aws_ecs_cluster", ID: "{{.name}}"},
+	{Type: "aws_ecs_task_definition", ID: "{{.arn}}"},
+	{Type: "aws_wafv2_web_acl", ID: "{{.id}}/{{.name}}/{{.scope}}"},
+	{Type: "aws_autoscaling_schedule", ID: "{{.autoscaling_group_name}}/{{.scheduled_action_name}}"},
+	{Type: "aws_appautoscaling_target", ID: "{{.service_namespace}}/{{.resource_id}}/{{.scalable_dimension}}"},
+	{Type: "aws_appautoscaling_policy", ID: "{{.service_namespace}}/{{.resource_id}}/{{.scalable_dimension}}/{{.name}}"},
+	{Type: "aws_ecs_service", ID: `{{last (split .cluster "/")}}/{{.name}}`},
+	{Type: "aws_cloudwatch_log_stream", ID: "{{.log_group_name}}:{{.name}}"},
+	{Type: "aws_route", ID: awsRouteID},
+	{Type: "aws_alb_target_group_attachment", Unsupported: true},
+	{Type: "aws_lb_target_group_attachment", Unsupported: true},
+	{Type: "aws_lakeformation_data_lake_settings", Unsupported: true},
+	{Type: "aws_lakeformation_permissions", Unsupported: true},
+	{Type: "aws_iam_policy_attachment", Unsupported: true},
+	{Type: "aws_acm_certificate_validation", Unsupported: true},
+	{Type: "aws_ami_copy", Unsupported: true},
+	// gcp resources
+	{Type: "google_bigquery_dataset_iam_member", ID: "projects/{{.project}}/datasets/{{.dataset_id}} {{.role}} {{.member}}"},
+	{Type: "google_bigquery_table_iam_member", ID: "{{.table_id}} {{.role}} {{.member}}"},
+	{Type: "google_service_account_iam_member", ID: "{{.service_account_id}} {{.role}} {{.member}}"},
+	{Type: "google_service_account_iam_binding", ID: "{{.service_account_id}} {{.role}}"},
+	{Type: "google_privateca_ca_pool_iam_member", ID: "{{.ca_pool}} {{.role}} {{.member}}" + googleConditionTitle},
+	{Type: "google_privateca_certificate_template_iam_member", ID: "{{.certificate_template}} {{.role}} {{.member}}"},
+	{Type: "google_cloud_run_service_iam_binding", ID: "{{.service}} {{.role}}"},
+	{Type: "google_kms_crypto_key_iam_binding", ID: "{{.crypto_key_id}} {{.role}}"},
+	{Type: "google_kms_crypto_key_iam_member", ID: "{{.crypto_key_id}} {{.role}} {{.member}}"},
+	{Type: "google_organization_iam_member", ID: "{{.org_id}} {{.role}} {{.member}}"},
+	{Type: "google_project_iam_member", ID: "{{.project}} {{.role}} {{.member}}" + googleConditionTitle},
+	{Type: "google_project_iam_binding", ID: "{{.project}} {{.role}}"},
+	{Type: "google_project_iam_custom_role", ID: "{{.project}} {{.id}}"},
+	{Type: "google_sql_database_instance", ID: "projects/{{.project}}/instances/{{.name}}"},
+	{Type: "google_sql_user", ID: "{{.project}}/{{.instance}}/{{.name}}"},
+	{Type: "google_iap_tunnel_instance_iam_binding", ID: "{{.instance}} {{.role}}", Unsupported: true},
+	{Type: "google_secret_manager_secret_iam_binding", ID: "{{.secret_id}} {{.role}}"},
+	{Type: "google_secret_manager_secret_iam_member", ID: "{{.secret_id}} {{.role}} {{.member}}"},
+	{Type: "google_secret_manager_secret", ID: "{{.name}}"},
+	{Type: "google_storage_bucket_iam_member", ID: "{{.bucket}} {{.role}} {{.member}}"},
+	{Type: "google_storage_bucket_iam_binding", ID: "{{.bucket}} {{.role}}"},
+	{Type: "google_tags_tag_key_iam_member", ID: "{{.tag_key}} {{.role}} {{.member}}"},
+	{Type: "google_compute_subnetwork_iam_binding", ID: "{{.subnetwork}} {{.role}}"},
+	{Type: "google_pubsub_topic_iam_binding", ID: "{{.topic}} {{.role}}"},
+	{Type: "google_pubsub_topic_iam_member", ID: "{{.topic}} {{.role}} {{.member}}" + googleConditionTitle},
+	{Type: "google_resource_manager_lien", ID: `{{replace .parent "projects/" ""}}/{{.name}}`},
+	{Type: "google_monitoring_uptime_check_config", ID: "{{.project}} {{.id}}"},
+	{Type: "google_monitoring_alert_policy", ID: "{{.project}} {{.name}}"},
+	{Type: "google_monitoring_notification_channel", ID: "{{.name}}"},
+	{Type: "google_storage_default_object", Unsupported: true},
+	{Type: "google_storage_default_object_acl", Unsupported: true},
+	{Type: "google_storage_bucket_acl", Unsupported: true},
+	{Type: "google_project_service_identity", Unsupported: true},
+	{Type: "google_project_default_service_accounts", Unsupported: true},
+	{Type: "google_compute_instance_from_template", Unsupported: true},
+	{Type: "google_pubsub_subscription_iam_member", Unsupported: true},
+	{Type: "google_pubsub_subscription_iam_binding", Unsupported: true},
+	{Type: "google_compute_instance_template", Unsupported: true},
+	// other resources
+	{Type: "local_file", Unsupported: true},
+	{Type: "tailscale_tailnet_key", Unsupported: true},
+}
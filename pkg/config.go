@@ -0,0 +1,147 @@
+package tfimportgen
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/kishaningithub/tf-import-gen/pkg/internal/parser"
+)
+
+// ConfigBlocks renders the import blocks, each followed by a resource block
+// populated from the attribute values of the resource, as a starting point for
+// the configuration of the destination code base. The resource block of a
+// resource with multiple instances is rendered once, from its first instance.
+// The resource blocks of resources within child modules are commented out, as
+// they would clash with root module resources of the same type and name.
+func (terraformImports TerraformImports) ConfigBlocks() string {
+	var configBlocksStr strings.Builder
+	renderedResources := make(map[string]bool)
+	for _, terraformImport := range terraformImports {
+		configBlocksStr.WriteString(fmt.Sprintln(terraformImport))
+		if !terraformImport.SupportsImport {
+			continue
+		}
+		address, err := parser.ParseAddress(terraformImport.ResourceAddress)
+		if err != nil {
+			continue
+		}
+		resource := address.WithoutKeys().String()
+		if renderedResources[resource] {
+			continue
+		}
+		renderedResources[resource] = true
+		configBlocksStr.WriteString(fmt.Sprintln(terraformImport.resourceBlock(address)))
+	}
+	return configBlocksStr.String()
+}
+
+func (terraformImport TerraformImport) resourceBlock(address parser.Address) string {
+	var comments strings.Builder
+	if address.Key != nil {
+		comments.WriteString(fmt.Sprintf("# generated from %s, add count or for_each for the other instances\n", terraformImport.ResourceAddress))
+	}
+	block := hclwrite.NewBlock("resource", []string{address.Type, address.Name})
+	if len(terraformImport.Provider) > 0 {
+		block.Body().SetAttributeRaw("provider", tokensForReference(terraformImport.Provider))
+		block.Body().AppendNewline()
+	}
+	// the id is assigned by the provider and cannot be configured
+	if terraformImport.schema != nil {
+		appendConfigurableAttributeValues(block.Body(), terraformImport.AttributeValues, terraformImport.sensitiveValues, terraformImport.schema, []string{"id"})
+	} else {
+		appendAttributeValues(block.Body(), terraformImport.AttributeValues, terraformImport.sensitiveValues, []string{"id"})
+	}
+	if len(address.Module) > 0 {
+		comments.WriteString(fmt.Sprintf("# belongs in %s, uncomment it there\n", address.WithoutKeys().ModulePath()))
+		return comments.String() + commentOut(formatBlock(block))
+	}
+	return comments.String() + formatBlock(block)
+}
+
+// appendAttributeValues appends the values to the body. Lists of objects are
+// appended as nested blocks, and null values as well as empty lists and maps
+// are left out, which is how the state represents nested blocks and unset
+// optional attributes. Attributes containing sensitive values are replaced by
+// a comment, so that secrets do not end up in the code base.
+func appendAttributeValues(body *hclwrite.Body, values map[string]any, sensitiveValues map[string]any, excludedNames []string) {
+	var blockNames []string
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		if slices.Contains(excludedNames, name) || values[name] == nil {
+			continue
+		}
+		value, isList := values[name].([]any)
+		isBlock := isList && isListOfObjects(value) && sensitiveValues[name] != true
+		if !isBlock && containsSensitiveValue(sensitiveValues[name]) {
+			appendSensitiveComment(body, name)
+			continue
+		}
+		switch value := values[name].(type) {
+		case []any:
+			if isBlock {
+				blockNames = append(blockNames, name)
+			} else if len(value) > 0 {
+				body.SetAttributeRaw(name, tokensForJsonValue(value))
+			}
+		case map[string]any:
+			if len(value) > 0 {
+				body.SetAttributeRaw(name, tokensForJsonValue(value))
+			}
+		default:
+			body.SetAttributeRaw(name, tokensForJsonValue(value))
+		}
+	}
+	for _, name := range blockNames {
+		for i, element := range values[name].([]any) {
+			body.AppendNewline()
+			nestedBlock := body.AppendNewBlock(name, nil)
+			appendAttributeValues(nestedBlock.Body(), element.(map[string]any), nestedSensitiveValues(sensitiveValues[name], i), nil)
+		}
+	}
+}
+
+func appendSensitiveComment(body *hclwrite.Body, name string) {
+	body.AppendUnstructuredTokens(hclwrite.Tokens{
+		{Type: hclsyntax.TokenComment, Bytes: []byte(fmt.Sprintf("# %s is sensitive and therefore left out\n", name))},
+	})
+}
+
+// containsSensitiveValue reports whether the sensitive values of an attribute
+// mark the attribute or any value within it as sensitive
+func containsSensitiveValue(sensitiveValue any) bool {
+	switch sensitiveValue := sensitiveValue.(type) {
+	case bool:
+		return sensitiveValue
+	case []any:
+		return slices.ContainsFunc(sensitiveValue, containsSensitiveValue)
+	case map[string]any:
+		return slices.ContainsFunc(slices.Collect(maps.Values(sensitiveValue)), containsSensitiveValue)
+	default:
+		return false
+	}
+}
+
+// nestedSensitiveValues returns the sensitive values of the i-th nested block
+func nestedSensitiveValues(sensitiveValue any, i int) map[string]any {
+	sensitiveValues, ok := sensitiveValue.([]any)
+	if !ok || i >= len(sensitiveValues) {
+		return nil
+	}
+	nestedSensitiveValues, _ := sensitiveValues[i].(map[string]any)
+	return nestedSensitiveValues
+}
+
+func isListOfObjects(values []any) bool {
+	if len(values) == 0 {
+		return false
+	}
+	for _, value := range values {
+		if _, ok := value.(map[string]any); !ok {
+			return false
+		}
+	}
+	return true
+}
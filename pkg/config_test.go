@@ -0,0 +1,228 @@
+package tfimportgen_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tfimportgen "github.com/kishaningithub/tf-import-gen/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImports_ShouldSerializeAsImportBlocksWithResourceConfiguration(t *testing.T) {
+	imports := tfimportgen.TerraformImports{
+		{
+			ResourceAddress: "aws_security_group.web",
+			ResourceID:      "sg-1",
+			SupportsImport:  true,
+			Provider:        "aws.eu",
+			AttributeValues: map[string]any{
+				"id":          "sg-1",
+				"name":        "web",
+				"description": nil,
+				"tags":        map[string]any{"Team": "platform"},
+				"egress":      []any{},
+				"ingress": []any{
+					map[string]any{"from_port": float64(443), "cidr_blocks": []any{"0.0.0.0/0"}, "description": "https ${x}"},
+				},
+			},
+		},
+		{
+			ResourceAddress: `module.storage.aws_s3_bucket.this["logs"]`,
+			ResourceID:      "logs",
+			SupportsImport:  true,
+			AttributeValues: map[string]any{"id": "logs", "bucket": "logs"},
+		},
+		{
+			ResourceAddress: `module.storage.aws_s3_bucket.this["assets"]`,
+			ResourceID:      "assets",
+			SupportsImport:  true,
+			AttributeValues: map[string]any{"id": "assets", "bucket": "assets"},
+		},
+		{
+			ResourceAddress: "aws_iam_policy_attachment.test",
+			ResourceID:      "test",
+			AttributeValues: map[string]any{"id": "test", "name": "test"},
+		},
+	}
+
+	expectedResult := `import {
+  to       = aws_security_group.web
+  id       = "sg-1"
+  provider = aws.eu
+}
+
+resource "aws_security_group" "web" {
+  provider = aws.eu
+
+  name = "web"
+  tags = {
+    Team = "platform"
+  }
+
+  ingress {
+    cidr_blocks = ["0.0.0.0/0"]
+    description = "https $${x}"
+    from_port   = 443
+  }
+}
+
+import {
+  to = module.storage.aws_s3_bucket.this["logs"]
+  id = "logs"
+}
+
+# generated from module.storage.aws_s3_bucket.this["logs"], add count or for_each for the other instances
+# belongs in module.storage, uncomment it there
+# resource "aws_s3_bucket" "this" {
+#   bucket = "logs"
+# }
+
+import {
+  to = module.storage.aws_s3_bucket.this["assets"]
+  id = "assets"
+}
+
+# resource "aws_iam_policy_attachment.test" with identifier "test" does not support import operation. Kindly refer resource documentation for more info.
+
+`
+
+	require.Equal(t, expectedResult, imports.ConfigBlocks())
+	actual, err := imports.Render(tfimportgen.FormatConfig)
+	require.NoError(t, err)
+	require.Equal(t, expectedResult, actual)
+	parseBlocks(t, actual)
+}
+
+func Test_GenerateImports_ShouldPopulateAttributeValuesWhenRequested(t *testing.T) {
+	state := `{"format_version": "1.0", "values": {"root_module": {"resources": [
+		{"address": "aws_iam_role.test", "mode": "managed", "type": "aws_iam_role", "name": "test", "values": {"id": "test-role", "name": "test-role"}}
+	]}}}`
+
+	withoutAttributeValues, err := tfimportgen.GenerateImports(bytes.NewBufferString(state), nil)
+	require.NoError(t, err)
+	withAttributeValues, err := tfimportgen.GenerateImports(bytes.NewBufferString(state), nil, tfimportgen.WithAttributeValues())
+	require.NoError(t, err)
+
+	require.Nil(t, withoutAttributeValues[0].AttributeValues)
+	require.Equal(t, map[string]any{"id": "test-role", "name": "test-role"}, withAttributeValues[0].AttributeValues)
+}
+
+func Test_GenerateImports_ShouldLeaveSensitiveValuesOutOfResourceConfiguration(t *testing.T) {
+	state := `{"format_version": "1.0", "values": {"root_module": {"resources": [
+		{"address": "aws_db_instance.main", "mode": "managed", "type": "aws_db_instance", "name": "main", "values": {
+			"id": "db-1", "instance_class": "db.t3.micro", "password": "secret",
+			"connection": {"user": "admin", "private_key": "-----BEGIN KEY-----"},
+			"setting": [{"name": "log", "value": "on"}, {"name": "token", "value": "abc"}]
+		}, "sensitive_values": {"password": true, "connection": {"private_key": true}, "setting": [{}, {"value": true}]}}
+	]}}}`
+
+	imports, err := tfimportgen.GenerateImports(bytes.NewBufferString(state), nil, tfimportgen.WithAttributeValues())
+	require.NoError(t, err)
+
+	expectedResult := `import {
+  to = aws_db_instance.main
+  id = "db-1"
+}
+
+resource "aws_db_instance" "main" {
+  # connection is sensitive and therefore left out
+  instance_class = "db.t3.micro"
+  # password is sensitive and therefore left out
+
+  setting {
+    name  = "log"
+    value = "on"
+  }
+
+  setting {
+    name = "token"
+    # value is sensitive and therefore left out
+  }
+}
+
+`
+	require.Equal(t, expectedResult, imports.ConfigBlocks())
+	parseBlocks(t, imports.ConfigBlocks())
+}
+
+func Test_GenerateImports_ShouldPruneResourceConfigurationUsingProviderSchemas(t *testing.T) {
+	state := `{"format_version": "1.0", "values": {"root_module": {"resources": [
+		{"address": "aws_db_instance.main", "mode": "managed", "type": "aws_db_instance", "name": "main", "provider_name": "registry.terraform.io/hashicorp/aws", "values": {
+			"id": "db-1", "arn": "arn:aws:rds:eu-west-1:123456789012:db:main", "identifier": "main", "instance_class": "db.t3.micro", "password": "secret",
+			"master_user_secret": {"kms_key_id": "key-1", "secret_string": "secret"},
+			"tags": {"Team": "platform"}, "tags_all": {"Team": "platform"},
+			"blue_green_update": [{"enabled": true}], "timeouts": {"create": "1h", "delete": null}
+		}},
+		{"address": "aws_s3_bucket.logs", "mode": "managed", "type": "aws_s3_bucket", "name": "logs", "provider_name": "registry.terraform.io/hashicorp/aws", "values": {"id": "logs", "bucket": "logs"}}
+	]}}}`
+	providerSchemasFile, err := os.Open(filepath.FromSlash("testdata/provider_schemas.json"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = providerSchemasFile.Close()
+	})
+	providerSchemas, err := tfimportgen.LoadProviderSchemas(providerSchemasFile)
+	require.NoError(t, err)
+
+	imports, err := tfimportgen.GenerateImports(bytes.NewBufferString(state), nil, tfimportgen.WithAttributeValues(), tfimportgen.WithProviderSchemas(providerSchemas))
+	require.NoError(t, err)
+
+	expectedResult := `import {
+  to = aws_db_instance.main
+  id = "db-1"
+}
+
+resource "aws_db_instance" "main" {
+  identifier     = "main"
+  instance_class = "db.t3.micro"
+  # master_user_secret is sensitive and therefore left out
+  # password is sensitive and therefore left out
+  tags = {
+    Team = "platform"
+  }
+
+  blue_green_update {
+    enabled = true
+  }
+
+  timeouts {
+    create = "1h"
+  }
+}
+
+import {
+  to = aws_s3_bucket.logs
+  id = "logs"
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+`
+	require.Equal(t, expectedResult, imports.ConfigBlocks())
+	require.Equal(t, []string{"aws_s3_bucket"}, providerSchemas.UnknownResourceTypes(imports))
+	parseBlocks(t, imports.ConfigBlocks())
+}
+
+func Test_LoadProviderSchemas_ShouldFailForInvalidSchemas(t *testing.T) {
+	_, err := tfimportgen.LoadProviderSchemas(bytes.NewBufferString("{"))
+	require.ErrorContains(t, err, "invalid provider schemas")
+}
+
+func TestImports_ConfigBlocksShouldNotDeclareResourcesOfChildModulesNextToRootResources(t *testing.T) {
+	imports := tfimportgen.TerraformImports{
+		{ResourceAddress: "aws_s3_bucket.this", ResourceID: "root", SupportsImport: true, AttributeValues: map[string]any{"bucket": "root"}},
+		{ResourceAddress: "module.a.aws_s3_bucket.this", ResourceID: "child", SupportsImport: true, AttributeValues: map[string]any{"bucket": "child"}},
+	}
+
+	var resourceBlocks int
+	for _, block := range parseBlocks(t, imports.ConfigBlocks()) {
+		if block.Type == "resource" {
+			resourceBlocks++
+		}
+	}
+
+	require.Equal(t, 1, resourceBlocks)
+}
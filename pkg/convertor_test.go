@@ -1106,13 +1106,136 @@ func Test_ComputeTerraformImportForResource(t *testing.T) {
 				ResourceAddress: "example.address",
 				ResourceID:      "test_id",
 				SupportsImport:  true,
+				DefaultIDRule:   true,
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			actual := computeTerraformImportForResource(tt.terraformResource)
+			actual := computeTerraformImportForResource(tt.terraformResource, defaultIDRuleRegistry)
 			require.Equal(t, tt.expected, actual)
 		})
 	}
 }
+
+func Test_ComputeTerraformImportForResource_ShouldDiagnoseAttributesWhichCannotFormTheID(t *testing.T) {
+	tests := []struct {
+		name                string
+		terraformResource   parser.TerraformResource
+		expectedDiagnostics []Diagnostic
+	}{
+		{
+			name: "missing attribute",
+			terraformResource: parser.TerraformResource{
+				Address:         "aws_iam_role_policy_attachment.test",
+				Type:            "aws_iam_role_policy_attachment",
+				AttributeValues: map[string]any{"role": "test-role"},
+			},
+			expectedDiagnostics: []Diagnostic{
+				{Kind: DiagnosticMissingAttribute, Address: "aws_iam_role_policy_attachment.test", Attribute: "policy_arn"},
+			},
+		},
+		{
+			name: "null attribute",
+			terraformResource: parser.TerraformResource{
+				Address:         "example.address",
+				Type:            "example_type",
+				AttributeValues: map[string]any{"id": nil},
+			},
+			expectedDiagnostics: []Diagnostic{
+				{Kind: DiagnosticMissingAttribute, Address: "example.address", Attribute: "id"},
+			},
+		},
+		{
+			name: "empty attribute",
+			terraformResource: parser.TerraformResource{
+				Address:         "aws_lambda_permission.test",
+				Type:            "aws_lambda_permission",
+				AttributeValues: map[string]any{"function_name": "", "statement_id": "test-statement-id"},
+			},
+			expectedDiagnostics: []Diagnostic{
+				{Kind: DiagnosticEmptyValue, Address: "aws_lambda_permission.test", Attribute: "function_name"},
+			},
+		},
+		{
+			name: "attribute of wrong type",
+			terraformResource: parser.TerraformResource{
+				Address:         "aws_ecs_cluster.test",
+				Type:            "aws_ecs_cluster",
+				AttributeValues: map[string]any{"name": []any{"a", "b"}},
+			},
+			expectedDiagnostics: []Diagnostic{
+				{Kind: DiagnosticWrongType, Address: "aws_ecs_cluster.test", Attribute: "name"},
+			},
+		},
+		{
+			name: "rule which fails to evaluate",
+			terraformResource: parser.TerraformResource{
+				Address:         "aws_ecs_service.test",
+				Type:            "aws_ecs_service",
+				AttributeValues: map[string]any{"cluster": []any{"a"}, "name": "service"},
+			},
+			expectedDiagnostics: []Diagnostic{
+				{
+					Kind:    DiagnosticInvalidRule,
+					Address: "aws_ecs_service.test",
+					Detail:  `template: aws_ecs_service:1:14: executing "aws_ecs_service" at <.cluster>: wrong type for value; expected string; got []interface {}`,
+				},
+			},
+		},
+		{
+			name: "optional attributes of a conditional rule",
+			terraformResource: parser.TerraformResource{
+				Address: "aws_route.test",
+				Type:    "aws_route",
+				AttributeValues: map[string]any{
+					"route_table_id":         "rtb-1",
+					"destination_cidr_block": "0.0.0.0/0",
+				},
+			},
+		},
+		{
+			name: "missing attribute in the branch of a conditional rule",
+			terraformResource: parser.TerraformResource{
+				Address:         "aws_route.test",
+				Type:            "aws_route",
+				AttributeValues: map[string]any{"route_table_id": "rtb-1"},
+			},
+			expectedDiagnostics: []Diagnostic{
+				{Kind: DiagnosticMissingAttribute, Address: "aws_route.test", Attribute: "destination_ipv6_cidr_block"},
+			},
+		},
+		{
+			name: "missing attribute within a nested block",
+			terraformResource: parser.TerraformResource{
+				Address: "google_project_iam_member.test",
+				Type:    "google_project_iam_member",
+				AttributeValues: map[string]any{
+					"project":   "p",
+					"role":      "r",
+					"member":    "m",
+					"condition": []any{map[string]any{"expression": "true"}},
+				},
+			},
+			expectedDiagnostics: []Diagnostic{
+				{Kind: DiagnosticMissingAttribute, Address: "google_project_iam_member.test", Attribute: "title"},
+			},
+		},
+		{
+			name: "resource which does not support import",
+			terraformResource: parser.TerraformResource{
+				Address: "aws_iam_policy_attachment.test",
+				Type:    "aws_iam_policy_attachment",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := computeTerraformImportForResource(tt.terraformResource, defaultIDRuleRegistry)
+			require.Equal(t, tt.expectedDiagnostics, actual.Diagnostics)
+			if len(tt.expectedDiagnostics) > 0 {
+				require.Empty(t, actual.ResourceID)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package tfimportgen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template/parse"
+
+	"github.com/kishaningithub/tf-import-gen/pkg/internal/parser"
+)
+
+// DiagnosticKind is the kind of problem found while computing the import
+// identifier of a resource
+type DiagnosticKind string
+
+const (
+	// DiagnosticMissingAttribute is reported when an attribute required for the
+	// import identifier is absent or null
+	DiagnosticMissingAttribute DiagnosticKind = "missing_attribute"
+	// DiagnosticWrongType is reported when an attribute required for the import
+	// identifier is a list or a map rather than a single value
+	DiagnosticWrongType DiagnosticKind = "wrong_type"
+	// DiagnosticEmptyValue is reported when an attribute required for the import
+	// identifier is an empty string
+	DiagnosticEmptyValue DiagnosticKind = "empty_value"
+	// DiagnosticInvalidRule is reported when the id rule of the resource type
+	// fails to evaluate against the attributes of the resource
+	DiagnosticInvalidRule DiagnosticKind = "invalid_rule"
+)
+
+// Diagnostic describes why the import identifier of a resource could not be
+// computed reliably
+type Diagnostic struct {
+	Kind DiagnosticKind `json:"kind"`
+	// Address is the address of the resource in the state
+	Address string `json:"address"`
+	// Attribute is the attribute of the resource the diagnostic is about. It is
+	// empty for DiagnosticInvalidRule.
+	Attribute string `json:"attribute,omitempty"`
+	// Detail is the error returned by the id rule for DiagnosticInvalidRule
+	Detail string `json:"detail,omitempty"`
+}
+
+func (diagnostic Diagnostic) String() string {
+	switch diagnostic.Kind {
+	case DiagnosticMissingAttribute:
+		return fmt.Sprintf("attribute %q required for the import id of %s is missing", diagnostic.Attribute, diagnostic.Address)
+	case DiagnosticWrongType:
+		return fmt.Sprintf("attribute %q required for the import id of %s is not a single value", diagnostic.Attribute, diagnostic.Address)
+	case DiagnosticEmptyValue:
+		return fmt.Sprintf("attribute %q required for the import id of %s is empty", diagnostic.Attribute, diagnostic.Address)
+	default:
+		return fmt.Sprintf("import id of %s could not be computed: %s", diagnostic.Address, diagnostic.Detail)
+	}
+}
+
+// requiredAttribute is an attribute which an id template always evaluates
+type requiredAttribute struct {
+	name string
+	// printed is set when the attribute is written into the identifier as it
+	// is, which rules out lists and maps
+	printed bool
+}
+
+// findRequiredAttributes returns the attributes referenced by the actions at
+// the top level of the template. Whether the attributes within if, with and
+// range are evaluated depends on the attribute values, so they are checked
+// while executing the template, see requireBranchAttributes.
+func findRequiredAttributes(tree *parse.Tree) []requiredAttribute {
+	var requiredAttributes []requiredAttribute
+	for _, node := range tree.Root.Nodes {
+		action, ok := node.(*parse.ActionNode)
+		if !ok || len(action.Pipe.Decl) > 0 {
+			continue
+		}
+		printed := len(action.Pipe.Cmds) == 1 && len(action.Pipe.Cmds[0].Args) == 1
+		for _, command := range action.Pipe.Cmds {
+			for _, arg := range command.Args {
+				field, ok := arg.(*parse.FieldNode)
+				if !ok {
+					continue
+				}
+				requiredAttributes = append(requiredAttributes, requiredAttribute{
+					name:    field.Ident[0],
+					printed: printed && len(field.Ident) == 1,
+				})
+			}
+		}
+	}
+	return requiredAttributes
+}
+
+func checkRequiredAttributes(resource parser.TerraformResource, requiredAttributes []requiredAttribute) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, attribute := range requiredAttributes {
+		diagnostic := Diagnostic{Address: resource.Address, Attribute: attribute.name}
+		switch value := resource.AttributeValues[attribute.name].(type) {
+		case nil:
+			diagnostic.Kind = DiagnosticMissingAttribute
+		case string:
+			if len(value) > 0 {
+				continue
+			}
+			diagnostic.Kind = DiagnosticEmptyValue
+		case []any, map[string]any:
+			if !attribute.printed {
+				continue
+			}
+			diagnostic.Kind = DiagnosticWrongType
+		default:
+			continue
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	return diagnostics
+}
+
+// requiredTemplateFunc is the template function which checks an attribute
+// printed within a branch of an id template when the branch is executed
+const requiredTemplateFunc = "required"
+
+// attributeError is returned by the required template function, so that an
+// attribute missing in the branch which ran is reported like a top level one
+type attributeError struct {
+	kind      DiagnosticKind
+	attribute string
+}
+
+func (err attributeError) Error() string {
+	return fmt.Sprintf("attribute %q is %s", err.attribute, err.kind)
+}
+
+func required(attribute string, value any) (any, error) {
+	switch value := value.(type) {
+	case nil:
+		return nil, attributeError{kind: DiagnosticMissingAttribute, attribute: attribute}
+	case string:
+		if len(value) == 0 {
+			return nil, attributeError{kind: DiagnosticEmptyValue, attribute: attribute}
+		}
+	case []any, map[string]any:
+		return nil, attributeError{kind: DiagnosticWrongType, attribute: attribute}
+	}
+	return value, nil
+}
+
+// requireBranchAttributes wraps the attributes printed within if, with and
+// range in the required function, e.g. {{.title}} becomes
+// {{required "title" .title}}. Otherwise a missing attribute would be printed
+// as <no value> without any diagnostic.
+func requireBranchAttributes(tree *parse.Tree) {
+	var walk func(list *parse.ListNode, nested bool)
+	walk = func(list *parse.ListNode, nested bool) {
+		if list == nil {
+			return
+		}
+		for _, node := range list.Nodes {
+			switch node := node.(type) {
+			case *parse.ActionNode:
+				if nested {
+					requireAttribute(tree, node)
+				}
+			case *parse.IfNode:
+				walk(node.List, true)
+				walk(node.ElseList, true)
+			case *parse.WithNode:
+				walk(node.List, true)
+				walk(node.ElseList, true)
+			case *parse.RangeNode:
+				walk(node.List, true)
+				walk(node.ElseList, true)
+			}
+		}
+	}
+	walk(tree.Root, false)
+}
+
+func requireAttribute(tree *parse.Tree, action *parse.ActionNode) {
+	if len(action.Pipe.Decl) > 0 || len(action.Pipe.Cmds) != 1 || len(action.Pipe.Cmds[0].Args) != 1 {
+		return
+	}
+	command := action.Pipe.Cmds[0]
+	field, ok := command.Args[0].(*parse.FieldNode)
+	if !ok {
+		return
+	}
+	attribute := strings.Join(field.Ident, ".")
+	command.Args = []parse.Node{
+		parse.NewIdentifier(requiredTemplateFunc).SetTree(tree).SetPos(field.Pos),
+		&parse.StringNode{NodeType: parse.NodeString, Pos: field.Pos, Quoted: strconv.Quote(attribute), Text: attribute},
+		field,
+	}
+}
@@ -0,0 +1,100 @@
+package tfimportgen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/kishaningithub/tf-import-gen/pkg/internal/parser"
+)
+
+// resourceInstanceImports are the imports of the instances of a resource which
+// can be rendered as a single for_each import block
+type resourceInstanceImports struct {
+	resource parser.Address
+	provider string
+	keys     []any
+	imports  TerraformImports
+}
+
+// ForEachBlocks renders the imports of all the instances of a resource as a
+// single import block iterating over the instance keys with for_each, which
+// requires terraform 1.7 or later. Resources with a single instance, resources
+// which do not support import and imports with diagnostics are rendered as in
+// String.
+func (terraformImports TerraformImports) ForEachBlocks() string {
+	var order []any
+	groupsByResource := make(map[string]*resourceInstanceImports)
+	for _, terraformImport := range terraformImports {
+		address, err := parser.ParseAddress(terraformImport.ResourceAddress)
+		if err != nil || address.Key == nil || !terraformImport.SupportsImport || len(terraformImport.Diagnostics) > 0 {
+			order = append(order, terraformImport)
+			continue
+		}
+		resource := address
+		resource.Key = nil
+		groupKey := resource.String() + " " + terraformImport.Provider
+		group, ok := groupsByResource[groupKey]
+		if !ok {
+			group = &resourceInstanceImports{resource: resource, provider: terraformImport.Provider}
+			groupsByResource[groupKey] = group
+			order = append(order, group)
+		}
+		group.keys = append(group.keys, address.Key)
+		group.imports = append(group.imports, terraformImport)
+	}
+
+	var forEachBlocksStr strings.Builder
+	for _, item := range order {
+		switch item := item.(type) {
+		case TerraformImport:
+			forEachBlocksStr.WriteString(fmt.Sprintln(item))
+		case *resourceInstanceImports:
+			forEachBlocksStr.WriteString(item.String())
+		}
+	}
+	return forEachBlocksStr.String()
+}
+
+func (group *resourceInstanceImports) String() string {
+	numericKeys, stringKeys := 0, 0
+	for _, key := range group.keys {
+		if _, ok := key.(int); ok {
+			numericKeys++
+		} else {
+			stringKeys++
+		}
+	}
+	if len(group.imports) == 1 || (numericKeys > 0 && stringKeys > 0) {
+		return group.imports.String()
+	}
+
+	var forEachAttributes []hclwrite.ObjectAttrTokens
+	for i, terraformImport := range group.imports {
+		forEachAttributes = append(forEachAttributes, hclwrite.ObjectAttrTokens{
+			Name:  tokensForString(fmt.Sprint(group.keys[i])),
+			Value: tokensForString(terraformImport.ResourceID),
+		})
+	}
+
+	// for_each maps always have string keys, so count indexes are converted back
+	key := tokensForReference("each.key")
+	if numericKeys > 0 {
+		key = hclwrite.TokensForFunctionCall("tonumber", key)
+	}
+	to := tokensForAddress(group.resource.String())
+	to = append(to, &hclwrite.Token{Type: hclsyntax.TokenOBrack, Bytes: []byte("[")})
+	to = append(to, key...)
+	to = append(to, &hclwrite.Token{Type: hclsyntax.TokenCBrack, Bytes: []byte("]")})
+
+	block := hclwrite.NewBlock("import", nil)
+	block.Body().SetAttributeRaw("for_each", hclwrite.TokensForObject(forEachAttributes))
+	block.Body().AppendNewline()
+	block.Body().SetAttributeRaw("to", to)
+	block.Body().SetAttributeRaw("id", tokensForReference("each.value"))
+	if len(group.provider) > 0 {
+		block.Body().SetAttributeRaw("provider", tokensForReference(group.provider))
+	}
+	return fmt.Sprintln(formatBlock(block))
+}
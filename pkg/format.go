@@ -0,0 +1,92 @@
+package tfimportgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// Format is the representation in which the imports are rendered
+type Format string
+
+const (
+	// FormatImport renders terraform import blocks
+	FormatImport Format = "import"
+	// FormatForEach renders one terraform import block with for_each per
+	// resource, which requires terraform 1.7 or later
+	FormatForEach Format = "for_each"
+	// FormatIdentity renders terraform import blocks using the resource
+	// identity where available, which requires terraform 1.12 or later
+	FormatIdentity Format = "identity"
+	// FormatConfig renders terraform import blocks followed by resource blocks
+	// populated from the attribute values in the state. The imports have to be
+	// generated WithAttributeValues.
+	FormatConfig Format = "config"
+	// FormatMoved renders terraform moved blocks for the resources whose
+	// address was rewritten, for use when source and destination share a state
+	FormatMoved Format = "moved"
+	// FormatRemoved renders terraform removed blocks which make the source code
+	// base forget the resources without destroying them
+	FormatRemoved Format = "removed"
+	// FormatCommand renders legacy `terraform import` shell commands for
+	// terraform versions older than 1.5
+	FormatCommand Format = "command"
+	// FormatJSON renders the imports as a json array for post processing
+	FormatJSON Format = "json"
+)
+
+// Formats are all the supported formats
+var Formats = []Format{
+	FormatImport,
+	FormatForEach,
+	FormatIdentity,
+	FormatConfig,
+	FormatMoved,
+	FormatRemoved,
+	FormatCommand,
+	FormatJSON,
+}
+
+// Render renders the imports in the given format
+func (terraformImports TerraformImports) Render(format Format) (string, error) {
+	switch format {
+	case FormatImport:
+		return terraformImports.String(), nil
+	case FormatForEach:
+		return terraformImports.ForEachBlocks(), nil
+	case FormatIdentity:
+		return terraformImports.IdentityBlocks(), nil
+	case FormatConfig:
+		return terraformImports.ConfigBlocks(), nil
+	case FormatMoved:
+		return terraformImports.MovedBlocks(), nil
+	case FormatRemoved:
+		return terraformImports.RemovedBlocks(), nil
+	case FormatCommand:
+		return terraformImports.Commands(), nil
+	case FormatJSON:
+		importsJson, err := json.MarshalIndent(terraformImports, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(importsJson), nil
+	default:
+		return "", fmt.Errorf("unsupported format %q, supported formats are %v", format, Formats)
+	}
+}
+
+// ForFormat returns the imports without the diagnostics which do not apply to
+// the format. FormatIdentity imports the resources which have an identity by
+// it, so that an import id which could not be computed does not matter.
+func (terraformImports TerraformImports) ForFormat(format Format) TerraformImports {
+	if format != FormatIdentity {
+		return terraformImports
+	}
+	formatImports := slices.Clone(terraformImports)
+	for i, terraformImport := range formatImports {
+		if terraformImport.SupportsImport && len(terraformImport.Identity) > 0 {
+			formatImports[i].Diagnostics = nil
+		}
+	}
+	return formatImports
+}
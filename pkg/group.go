@@ -0,0 +1,46 @@
+package tfimportgen
+
+import (
+	"github.com/kishaningithub/tf-import-gen/pkg/internal/parser"
+)
+
+// ModuleImports are the imports of the resources within a module
+type ModuleImports struct {
+	// ModulePath is the path of the module without instance keys, e.g.
+	// module.app.module.child. It is empty for the root module.
+	ModulePath string
+	// ModuleNames are the names of the modules along the path, e.g. [app child]
+	ModuleNames []string
+	Imports     TerraformImports
+}
+
+// GroupByModule groups the imports by the module of their resource address, in
+// the order in which the modules first appear. All the instances of a module
+// share a group. When topLevelOnly is set, the resources of child modules are
+// grouped with their top level module.
+func (terraformImports TerraformImports) GroupByModule(topLevelOnly bool) []ModuleImports {
+	var groups []ModuleImports
+	groupIndexes := make(map[string]int)
+	for _, terraformImport := range terraformImports {
+		var module []parser.ModuleInstance
+		if address, err := parser.ParseAddress(terraformImport.ResourceAddress); err == nil {
+			module = address.WithoutKeys().Module
+		}
+		if topLevelOnly && len(module) > 1 {
+			module = module[:1]
+		}
+		modulePath := parser.Address{Module: module}.ModulePath()
+		groupIndex, ok := groupIndexes[modulePath]
+		if !ok {
+			var moduleNames []string
+			for _, moduleInstance := range module {
+				moduleNames = append(moduleNames, moduleInstance.Name)
+			}
+			groupIndex = len(groups)
+			groupIndexes[modulePath] = groupIndex
+			groups = append(groups, ModuleImports{ModulePath: modulePath, ModuleNames: moduleNames})
+		}
+		groups[groupIndex].Imports = append(groups[groupIndex].Imports, terraformImport)
+	}
+	return groups
+}
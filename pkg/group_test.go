@@ -0,0 +1,27 @@
+package tfimportgen_test
+
+import (
+	"testing"
+
+	tfimportgen "github.com/kishaningithub/tf-import-gen/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImports_GroupByModule(t *testing.T) {
+	rootImport := tfimportgen.TerraformImport{ResourceAddress: "aws_instance.web", ResourceID: "i-1", SupportsImport: true}
+	appImport := tfimportgen.TerraformImport{ResourceAddress: `module.app["a"].aws_instance.web`, ResourceID: "i-2", SupportsImport: true}
+	otherAppInstanceImport := tfimportgen.TerraformImport{ResourceAddress: `module.app["b"].aws_instance.web`, ResourceID: "i-3", SupportsImport: true}
+	childImport := tfimportgen.TerraformImport{ResourceAddress: "module.app.module.child.aws_instance.web", ResourceID: "i-4", SupportsImport: true}
+	imports := tfimportgen.TerraformImports{appImport, rootImport, childImport, otherAppInstanceImport}
+
+	require.Equal(t, []tfimportgen.ModuleImports{
+		{ModulePath: "module.app", ModuleNames: []string{"app"}, Imports: tfimportgen.TerraformImports{appImport, otherAppInstanceImport}},
+		{ModulePath: "", Imports: tfimportgen.TerraformImports{rootImport}},
+		{ModulePath: "module.app.module.child", ModuleNames: []string{"app", "child"}, Imports: tfimportgen.TerraformImports{childImport}},
+	}, imports.GroupByModule(false))
+
+	require.Equal(t, []tfimportgen.ModuleImports{
+		{ModulePath: "module.app", ModuleNames: []string{"app"}, Imports: tfimportgen.TerraformImports{appImport, childImport, otherAppInstanceImport}},
+		{ModulePath: "", Imports: tfimportgen.TerraformImports{rootImport}},
+	}, imports.GroupByModule(true))
+}
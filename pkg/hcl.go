@@ -0,0 +1,104 @@
+package tfimportgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/kishaningithub/tf-import-gen/pkg/internal/parser"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// formatBlock renders the block formatted like `terraform fmt` does
+func formatBlock(block *hclwrite.Block) string {
+	file := hclwrite.NewEmptyFile()
+	file.Body().AppendBlock(block)
+	return string(hclwrite.Format(file.Bytes()))
+}
+
+// tokensForAddress renders the address as a traversal, in which the instance
+// keys are escaped according to the HCL rules. Addresses which cannot be
+// parsed are rendered as they are.
+func tokensForAddress(address string) hclwrite.Tokens {
+	parsedAddress, err := parser.ParseAddress(address)
+	if err != nil {
+		return hclwrite.Tokens{{Type: hclsyntax.TokenIdent, Bytes: []byte(address)}}
+	}
+	return hclwrite.TokensForTraversal(traversalForAddress(parsedAddress))
+}
+
+func traversalForAddress(address parser.Address) hcl.Traversal {
+	var traversal hcl.Traversal
+	appendName := func(name string) {
+		if len(traversal) == 0 {
+			traversal = append(traversal, hcl.TraverseRoot{Name: name})
+		} else {
+			traversal = append(traversal, hcl.TraverseAttr{Name: name})
+		}
+	}
+	appendKey := func(key any) {
+		switch key := key.(type) {
+		case nil:
+		case int:
+			traversal = append(traversal, hcl.TraverseIndex{Key: cty.NumberIntVal(int64(key))})
+		default:
+			traversal = append(traversal, hcl.TraverseIndex{Key: cty.StringVal(fmt.Sprint(key))})
+		}
+	}
+	for _, moduleInstance := range address.Module {
+		appendName("module")
+		appendName(moduleInstance.Name)
+		appendKey(moduleInstance.Key)
+	}
+	if address.IsModule() {
+		return traversal
+	}
+	if address.Mode == "data" {
+		appendName("data")
+	}
+	appendName(address.Type)
+	appendName(address.Name)
+	appendKey(address.Key)
+	return traversal
+}
+
+// tokensForReference renders a dotted reference like aws.us_east_1 or each.key
+func tokensForReference(reference string) hclwrite.Tokens {
+	var traversal hcl.Traversal
+	for i, name := range strings.Split(reference, ".") {
+		if i == 0 {
+			traversal = append(traversal, hcl.TraverseRoot{Name: name})
+		} else {
+			traversal = append(traversal, hcl.TraverseAttr{Name: name})
+		}
+	}
+	return hclwrite.TokensForTraversal(traversal)
+}
+
+// tokensForString renders the value as a quoted string, in which quotes,
+// backslashes and the template sequences ${ and %{ are escaped
+func tokensForString(value string) hclwrite.Tokens {
+	return hclwrite.TokensForValue(cty.StringVal(value))
+}
+
+// tokensForJsonValue renders a value decoded from json, like the attributes and
+// the identity of resources
+func tokensForJsonValue(value any) hclwrite.Tokens {
+	valueJson, err := json.Marshal(value)
+	if err != nil {
+		return tokensForString(fmt.Sprint(value))
+	}
+	valueType, err := ctyjson.ImpliedType(valueJson)
+	if err != nil {
+		return tokensForString(fmt.Sprint(value))
+	}
+	ctyValue, err := ctyjson.Unmarshal(valueJson, valueType)
+	if err != nil {
+		return tokensForString(fmt.Sprint(value))
+	}
+	return hclwrite.TokensForValue(ctyValue)
+}
@@ -0,0 +1,157 @@
+package tfimportgen_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	tfimportgen "github.com/kishaningithub/tf-import-gen/pkg"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// parseBlocks parses the rendered output back, failing the test when it is
+// not valid HCL
+func parseBlocks(t *testing.T, rendered string) hclsyntax.Blocks {
+	t.Helper()
+	file, diagnostics := hclsyntax.ParseConfig([]byte(rendered), "imports.tf", hcl.InitialPos)
+	require.False(t, diagnostics.HasErrors(), "%s\n%s", diagnostics.Error(), rendered)
+	return file.Body.(*hclsyntax.Body).Blocks
+}
+
+func requireTraversal(t *testing.T, expected string, expression hclsyntax.Expression) {
+	t.Helper()
+	traversal, diagnostics := hcl.AbsTraversalForExpr(expression)
+	require.False(t, diagnostics.HasErrors(), diagnostics.Error())
+	expectedTraversal, diagnostics := hclsyntax.ParseTraversalAbs([]byte(expected), "", hcl.InitialPos)
+	require.False(t, diagnostics.HasErrors(), diagnostics.Error())
+	require.Equal(t, len(expectedTraversal), len(traversal))
+	for i := range traversal {
+		switch step := traversal[i].(type) {
+		case hcl.TraverseIndex:
+			require.True(t, step.Key.RawEquals(expectedTraversal[i].(hcl.TraverseIndex).Key), "key %#v of %s", step.Key, expected)
+		case hcl.TraverseRoot:
+			require.Equal(t, expectedTraversal[i].(hcl.TraverseRoot).Name, step.Name)
+		case hcl.TraverseAttr:
+			require.Equal(t, expectedTraversal[i].(hcl.TraverseAttr).Name, step.Name)
+		}
+	}
+}
+
+func requireStringValue(t *testing.T, expected string, expression hclsyntax.Expression) {
+	t.Helper()
+	value, diagnostics := expression.Value(nil)
+	require.False(t, diagnostics.HasErrors(), diagnostics.Error())
+	require.True(t, value.RawEquals(cty.StringVal(expected)), "%#v is not %q", value, expected)
+}
+
+var escapingImports = tfimportgen.TerraformImports{
+	{
+		ResourceAddress: `module.app["a\"b"].aws_iam_role.this["$${team}"]`,
+		ResourceID:      `role "quoted" \ with ${interpolation} and %{directive}`,
+		SupportsImport:  true,
+		SourceAddress:   `aws_iam_role.this["%%{team}"]`,
+	},
+	{
+		ResourceAddress: `module.app["a\"b"].aws_iam_role.this["back\\slash"]`,
+		ResourceID:      "line\nbreak",
+		SupportsImport:  true,
+		Provider:        "aws.us_east_1",
+		Identity:        map[string]any{"name": "${name}", "path": `\`},
+	},
+}
+
+func TestImports_ShouldRenderImportBlocksWhichParseBack(t *testing.T) {
+	blocks := parseBlocks(t, escapingImports.String())
+
+	require.Len(t, blocks, 2)
+	requireTraversal(t, `module.app["a\"b"].aws_iam_role.this["$${team}"]`, blocks[0].Body.Attributes["to"].Expr)
+	requireStringValue(t, `role "quoted" \ with ${interpolation} and %{directive}`, blocks[0].Body.Attributes["id"].Expr)
+	requireTraversal(t, `module.app["a\"b"].aws_iam_role.this["back\\slash"]`, blocks[1].Body.Attributes["to"].Expr)
+	requireStringValue(t, "line\nbreak", blocks[1].Body.Attributes["id"].Expr)
+	requireTraversal(t, "aws.us_east_1", blocks[1].Body.Attributes["provider"].Expr)
+}
+
+func TestImports_ShouldRenderForEachBlocksWhichParseBack(t *testing.T) {
+	imports := tfimportgen.TerraformImports{
+		{ResourceAddress: `aws_iam_role.this["$${team}"]`, ResourceID: `role "quoted" ${interpolation}`, SupportsImport: true},
+		{ResourceAddress: `aws_iam_role.this["back\\slash"]`, ResourceID: "%{directive}", SupportsImport: true},
+	}
+
+	blocks := parseBlocks(t, imports.ForEachBlocks())
+
+	require.Len(t, blocks, 1)
+	forEach, diagnostics := blocks[0].Body.Attributes["for_each"].Expr.Value(nil)
+	require.False(t, diagnostics.HasErrors(), diagnostics.Error())
+	require.True(t, forEach.RawEquals(cty.ObjectVal(map[string]cty.Value{
+		"${team}":    cty.StringVal(`role "quoted" ${interpolation}`),
+		`back\slash`: cty.StringVal("%{directive}"),
+	})), "%#v", forEach)
+}
+
+func TestImports_ShouldRenderIdentityBlocksWhichParseBack(t *testing.T) {
+	blocks := parseBlocks(t, escapingImports.IdentityBlocks())
+
+	require.Len(t, blocks, 2)
+	identity, diagnostics := blocks[1].Body.Attributes["identity"].Expr.Value(nil)
+	require.False(t, diagnostics.HasErrors(), diagnostics.Error())
+	require.Equal(t, "${name}", identity.GetAttr("name").AsString())
+	require.Equal(t, `\`, identity.GetAttr("path").AsString())
+}
+
+func TestImports_ShouldRenderMovedAndRemovedBlocksWhichParseBack(t *testing.T) {
+	movedBlocks := parseBlocks(t, escapingImports.MovedBlocks())
+	removedBlocks := parseBlocks(t, escapingImports.RemovedBlocks())
+
+	require.Len(t, movedBlocks, 1)
+	requireTraversal(t, `aws_iam_role.this["%%{team}"]`, movedBlocks[0].Body.Attributes["from"].Expr)
+	requireTraversal(t, `module.app["a\"b"].aws_iam_role.this["$${team}"]`, movedBlocks[0].Body.Attributes["to"].Expr)
+	require.Len(t, removedBlocks, 2)
+	requireTraversal(t, "aws_iam_role.this", removedBlocks[0].Body.Attributes["from"].Expr)
+	requireTraversal(t, "module.app.aws_iam_role.this", removedBlocks[1].Body.Attributes["from"].Expr)
+}
+
+func TestImports_ShouldRenderResourcesWhichDoNotSupportImportAsCommentsWhichParseBack(t *testing.T) {
+	imports := tfimportgen.TerraformImports{
+		{ResourceAddress: "aws_iam_policy_attachment.this", ResourceID: "multi\nline"},
+		{ResourceAddress: "aws_iam_role.this", ResourceID: "role", SupportsImport: true, AttributeValues: map[string]any{"name": "role"}},
+	}
+
+	tests := []struct {
+		format         tfimportgen.Format
+		expectedBlocks int
+	}{
+		{format: tfimportgen.FormatImport, expectedBlocks: 1},
+		{format: tfimportgen.FormatForEach, expectedBlocks: 1},
+		{format: tfimportgen.FormatConfig, expectedBlocks: 2},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			rendered, err := imports.Render(tt.format)
+			require.NoError(t, err)
+
+			require.Len(t, parseBlocks(t, rendered), tt.expectedBlocks)
+			require.Contains(t, rendered, "\n# line\" does not support import operation")
+		})
+	}
+}
+
+func Test_GenerateImports_ShouldRenderGoogleConditionTitlesWhichParseBack(t *testing.T) {
+	stateJsonFile, err := os.Open(filepath.FromSlash("testdata/google.json"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = stateJsonFile.Close()
+	})
+	imports, err := tfimportgen.GenerateImports(stateJsonFile, nil)
+	require.NoError(t, err)
+
+	blocks := parseBlocks(t, imports.String())
+
+	require.Len(t, blocks, len(imports))
+	for i, block := range blocks {
+		requireTraversal(t, imports[i].ResourceAddress, block.Body.Attributes["to"].Expr)
+		requireStringValue(t, imports[i].ResourceID, block.Body.Attributes["id"].Expr)
+	}
+}
@@ -1,31 +1,211 @@
 package tfimportgen
 
 import (
+	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/kishaningithub/tf-import-gen/pkg/internal/parser"
+	"github.com/zclconf/go-cty/cty"
 )
 
 var _ fmt.Stringer = TerraformImport{}
+var _ json.Marshaler = TerraformImport{}
 
 type TerraformImport struct {
 	ResourceAddress string
 	ResourceID      string
 	SupportsImport  bool
+	// SourceAddress is the address of the resource in the source state. It is
+	// only populated when an address mapping rewrote the ResourceAddress.
+	SourceAddress string
+	// Provider is the reference to the non default provider configuration
+	// which manages the resource, e.g. aws.us_east_1. It is empty for
+	// resources managed by the default provider configuration.
+	Provider string
+	// Diagnostics are the problems found while computing the ResourceID. When
+	// there are any, the ResourceID is empty and the import is rendered
+	// commented out.
+	Diagnostics []Diagnostic
+	// Identity is the resource identity recorded by terraform 1.12 or later,
+	// which can be used instead of the ResourceID. It is nil for the resources
+	// of providers which do not support resource identity.
+	Identity map[string]any
+	// DefaultIDRule is set when the resource type has no id rule, so that the
+	// resource is imported using its id attribute, which is worth double checking
+	DefaultIDRule bool
+	// AttributeValues are the attribute values of the resource in the state.
+	// They are only populated when generated WithAttributeValues.
+	AttributeValues map[string]any
+	// schema is the schema of the resource type, which is only set when
+	// generated WithAttributeValues and WithProviderSchemas
+	schema *tfjson.SchemaBlock
+	// providerName is the source address of the provider the schema is looked
+	// up for, which is only set when generated WithAttributeValues
+	providerName string
+	// sensitiveValues mirrors AttributeValues with true for the sensitive values
+	sensitiveValues map[string]any
 }
 
 func (terraformImport TerraformImport) String() string {
-	importTemplate := `import {
-  to = %s
-  id = "%s"
-}`
 	if !terraformImport.SupportsImport {
-		importTemplate = `# resource "%s" with identifier "%s" does not support import operation. Kindly refer resource documentation for more info.`
+		return commentOut(fmt.Sprintf("resource \"%s\" with identifier \"%s\" does not support import operation. Kindly refer resource documentation for more info.\n",
+			terraformImport.ResourceAddress, terraformImport.ResourceID))
+	}
+	block := hclwrite.NewBlock("import", nil)
+	block.Body().SetAttributeRaw("to", tokensForAddress(terraformImport.ResourceAddress))
+	block.Body().SetAttributeRaw("id", tokensForString(terraformImport.ResourceID))
+	if len(terraformImport.Provider) > 0 {
+		block.Body().SetAttributeRaw("provider", tokensForReference(terraformImport.Provider))
+	}
+	return terraformImport.withWarnings(formatBlock(block))
+}
+
+// withWarnings comments out the rendered import and precedes it with the
+// diagnostics when there are any
+func (terraformImport TerraformImport) withWarnings(rendered string) string {
+	if len(terraformImport.Diagnostics) == 0 {
+		return rendered
+	}
+	var renderedStr strings.Builder
+	for _, diagnostic := range terraformImport.Diagnostics {
+		renderedStr.WriteString(commentOut(fmt.Sprintf("warning: %s\n", diagnostic)))
+	}
+	renderedStr.WriteString(commentOut(rendered))
+	return renderedStr.String()
+}
+
+// commentOut prefixes every line with #, so that values containing newlines
+// cannot end the comment
+func commentOut(rendered string) string {
+	var renderedStr strings.Builder
+	for _, line := range strings.SplitAfter(rendered, "\n") {
+		if len(line) > 0 {
+			renderedStr.WriteString("# " + line)
+		}
+	}
+	return renderedStr.String()
+}
+
+type terraformImportJson struct {
+	Address           string         `json:"address"`
+	SourceAddress     string         `json:"source_address,omitempty"`
+	Type              string         `json:"type"`
+	ModulePath        string         `json:"module_path"`
+	InstanceKey       any            `json:"instance_key"`
+	Provider          string         `json:"provider,omitempty"`
+	ID                string         `json:"id"`
+	SupportsImport    bool           `json:"supports_import"`
+	UnsupportedReason string         `json:"unsupported_reason,omitempty"`
+	Diagnostics       []Diagnostic   `json:"diagnostics,omitempty"`
+	Identity          map[string]any `json:"identity,omitempty"`
+}
+
+func (terraformImport TerraformImport) MarshalJSON() ([]byte, error) {
+	importJson := terraformImportJson{
+		Address:        terraformImport.ResourceAddress,
+		SourceAddress:  terraformImport.SourceAddress,
+		Provider:       terraformImport.Provider,
+		ID:             terraformImport.ResourceID,
+		SupportsImport: terraformImport.SupportsImport,
+		Diagnostics:    terraformImport.Diagnostics,
+		Identity:       terraformImport.Identity,
+	}
+	if address, err := parser.ParseAddress(terraformImport.ResourceAddress); err == nil {
+		importJson.Type = address.Type
+		importJson.ModulePath = address.ModulePath()
+		importJson.InstanceKey = address.Key
+	}
+	if !terraformImport.SupportsImport {
+		importJson.UnsupportedReason = fmt.Sprintf("resource type %s does not support import operation", importJson.Type)
+	}
+	return json.Marshal(importJson)
+}
+
+// IdentityBlock renders an import block which identifies the resource by its
+// resource identity, which requires terraform 1.12 or later. Resources without
+// identity are rendered as in String.
+func (terraformImport TerraformImport) IdentityBlock() string {
+	if len(terraformImport.Identity) == 0 || !terraformImport.SupportsImport {
+		return terraformImport.String()
+	}
+	block := hclwrite.NewBlock("import", nil)
+	block.Body().SetAttributeRaw("to", tokensForAddress(terraformImport.ResourceAddress))
+	if len(terraformImport.Provider) > 0 {
+		block.Body().SetAttributeRaw("provider", tokensForReference(terraformImport.Provider))
 	}
+	block.Body().AppendNewline()
+	var identityAttributes []hclwrite.ObjectAttrTokens
+	for _, attribute := range slices.Sorted(maps.Keys(terraformImport.Identity)) {
+		if terraformImport.Identity[attribute] == nil {
+			continue
+		}
+		identityAttributes = append(identityAttributes, hclwrite.ObjectAttrTokens{
+			Name:  hclwrite.TokensForIdentifier(attribute),
+			Value: tokensForJsonValue(terraformImport.Identity[attribute]),
+		})
+	}
+	block.Body().SetAttributeRaw("identity", hclwrite.TokensForObject(identityAttributes))
+	return formatBlock(block)
+}
 
-	return fmt.Sprintln(fmt.Sprintf(importTemplate, terraformImport.ResourceAddress, terraformImport.ResourceID))
+// Command renders the legacy `terraform import` command for terraform versions
+// which do not support import blocks
+func (terraformImport TerraformImport) Command() string {
+	if !terraformImport.SupportsImport {
+		return commentOut(fmt.Sprintf("resource %s with identifier %s does not support import operation. Kindly refer resource documentation for more info.\n",
+			quoteForShell(terraformImport.ResourceAddress), quoteForShell(terraformImport.ResourceID)))
+	}
+	return terraformImport.withWarnings(fmt.Sprintf("terraform import %s %s\n", quoteForShell(terraformImport.ResourceAddress), quoteForShell(terraformImport.ResourceID)))
+}
+
+// quoteForShell wraps the value in single quotes so that a POSIX shell does not
+// interpret brackets, double quotes, spaces or dollar signs in it
+func quoteForShell(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// MovedBlock renders a moved block from the source address to the resource
+// address. It is empty when the address of the resource was not rewritten.
+func (terraformImport TerraformImport) MovedBlock() string {
+	if len(terraformImport.SourceAddress) == 0 {
+		return ""
+	}
+	block := hclwrite.NewBlock("moved", nil)
+	block.Body().SetAttributeRaw("from", tokensForAddress(terraformImport.SourceAddress))
+	block.Body().SetAttributeRaw("to", tokensForAddress(terraformImport.ResourceAddress))
+	return formatBlock(block)
+}
+
+// RemovedBlock renders a removed block which makes the source code base forget
+// the resource without destroying it. As terraform requires, the block
+// addresses the resource as a whole rather than the individual instance.
+func (terraformImport TerraformImport) RemovedBlock() string {
+	from := terraformImport.sourceAddress()
+	if address, err := parser.ParseAddress(from); err == nil {
+		from = address.WithoutKeys().String()
+	}
+	block := hclwrite.NewBlock("removed", nil)
+	block.Body().SetAttributeRaw("from", tokensForAddress(from))
+	block.Body().AppendNewline()
+	lifecycle := block.Body().AppendNewBlock("lifecycle", nil)
+	lifecycle.Body().SetAttributeValue("destroy", cty.False)
+	return formatBlock(block)
+}
+
+func (terraformImport TerraformImport) sourceAddress() string {
+	if len(terraformImport.SourceAddress) > 0 {
+		return terraformImport.SourceAddress
+	}
+	return terraformImport.ResourceAddress
 }
 
 var _ fmt.Stringer = (TerraformImports)(nil)
+var _ json.Marshaler = (TerraformImports)(nil)
 
 type TerraformImports []TerraformImport
 
@@ -36,3 +216,66 @@ func (terraformImports TerraformImports) String() string {
 	}
 	return terraformImportsStr.String()
 }
+
+// MarshalJSON renders the imports as an array, which is empty rather than null
+// when there are no imports
+func (terraformImports TerraformImports) MarshalJSON() ([]byte, error) {
+	if terraformImports == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]TerraformImport(terraformImports))
+}
+
+// Diagnostics returns the diagnostics of all the imports
+func (terraformImports TerraformImports) Diagnostics() []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, terraformImport := range terraformImports {
+		diagnostics = append(diagnostics, terraformImport.Diagnostics...)
+	}
+	return diagnostics
+}
+
+// Commands renders one legacy `terraform import` command per line
+func (terraformImports TerraformImports) Commands() string {
+	var commandsStr strings.Builder
+	for _, terraformImport := range terraformImports {
+		commandsStr.WriteString(terraformImport.Command())
+	}
+	return commandsStr.String()
+}
+
+// IdentityBlocks renders import blocks using the resource identity of the
+// resources which have one and the import identifier of the others
+func (terraformImports TerraformImports) IdentityBlocks() string {
+	var identityBlocksStr strings.Builder
+	for _, terraformImport := range terraformImports {
+		identityBlocksStr.WriteString(fmt.Sprintln(terraformImport.IdentityBlock()))
+	}
+	return identityBlocksStr.String()
+}
+
+// MovedBlocks renders moved blocks for all the imports whose address was rewritten
+func (terraformImports TerraformImports) MovedBlocks() string {
+	var movedBlocksStr strings.Builder
+	for _, terraformImport := range terraformImports {
+		if movedBlock := terraformImport.MovedBlock(); len(movedBlock) > 0 {
+			movedBlocksStr.WriteString(fmt.Sprintln(movedBlock))
+		}
+	}
+	return movedBlocksStr.String()
+}
+
+// RemovedBlocks renders one removed block per resource in the source code base
+func (terraformImports TerraformImports) RemovedBlocks() string {
+	var removedBlocksStr strings.Builder
+	renderedRemovedBlocks := make(map[string]bool)
+	for _, terraformImport := range terraformImports {
+		removedBlock := terraformImport.RemovedBlock()
+		if renderedRemovedBlocks[removedBlock] {
+			continue
+		}
+		renderedRemovedBlocks[removedBlock] = true
+		removedBlocksStr.WriteString(fmt.Sprintln(removedBlock))
+	}
+	return removedBlocksStr.String()
+}
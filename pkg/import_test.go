@@ -1,6 +1,7 @@
 package tfimportgen_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/kishaningithub/tf-import-gen/pkg"
 	"github.com/stretchr/testify/require"
@@ -69,3 +70,344 @@ func TestImport_ShouldGenerateHelpfulMessageWhenResourceDoesNotSupportImport(t *
 
 	require.Equal(t, fmt.Sprintln(expectedResult), fmt.Sprint(tfImport))
 }
+
+func TestImports_ShouldSerializeAsMovedBlocksForRewrittenAddresses(t *testing.T) {
+	imports := tfimportgen.TerraformImports{
+		{
+			ResourceAddress: "aws_glue_catalog_database.test_db",
+			ResourceID:      "id_test_db",
+			SupportsImport:  true,
+		},
+		{
+			ResourceAddress: "module.new.aws_iam_instance_profile.test_instance_profile",
+			ResourceID:      "id_test_instance_profile",
+			SupportsImport:  true,
+			SourceAddress:   "module.old.aws_iam_instance_profile.test_instance_profile",
+		},
+	}
+
+	expectedResult := `moved {
+  from = module.old.aws_iam_instance_profile.test_instance_profile
+  to   = module.new.aws_iam_instance_profile.test_instance_profile
+}
+
+`
+
+	require.Equal(t, expectedResult, imports.MovedBlocks())
+	actual, err := imports.Render(tfimportgen.FormatMoved)
+	require.NoError(t, err)
+	require.Equal(t, expectedResult, actual)
+}
+
+func TestImports_ShouldRejectUnsupportedFormat(t *testing.T) {
+	_, err := tfimportgen.TerraformImports{}.Render("yaml")
+
+	require.EqualError(t, err, `unsupported format "yaml", supported formats are [import for_each identity config moved removed command json]`)
+}
+
+func TestImports_ShouldSerializeAsRemovedBlocksPerResource(t *testing.T) {
+	imports := tfimportgen.TerraformImports{
+		{
+			ResourceAddress: `module.app["a"].aws_s3_bucket.logs[0]`,
+			ResourceID:      "logs-0",
+			SupportsImport:  true,
+		},
+		{
+			ResourceAddress: `module.app["b"].aws_s3_bucket.logs[1]`,
+			ResourceID:      "logs-1",
+			SupportsImport:  true,
+		},
+		{
+			ResourceAddress: `module.new.aws_s3_bucket.this["k[0]"]`,
+			ResourceID:      "this",
+			SupportsImport:  true,
+			SourceAddress:   `aws_s3_bucket.this["k[0]"]`,
+		},
+	}
+
+	expectedResult := `removed {
+  from = module.app.aws_s3_bucket.logs
+
+  lifecycle {
+    destroy = false
+  }
+}
+
+removed {
+  from = aws_s3_bucket.this
+
+  lifecycle {
+    destroy = false
+  }
+}
+
+`
+
+	require.Equal(t, expectedResult, imports.RemovedBlocks())
+	actual, err := imports.Render(tfimportgen.FormatRemoved)
+	require.NoError(t, err)
+	require.Equal(t, expectedResult, actual)
+}
+
+func TestImports_ShouldSerializeAsTerraformImportCommands(t *testing.T) {
+	imports := tfimportgen.TerraformImports{
+		{
+			ResourceAddress: "aws_glue_catalog_database.test_db",
+			ResourceID:      "id_test_db",
+			SupportsImport:  true,
+		},
+		{
+			ResourceAddress: `module.x.aws_iam_role.z["it's"]`,
+			ResourceID:      "role $name",
+			SupportsImport:  true,
+		},
+		{
+			ResourceAddress: "aws_alb_target_group_attachment.test_alb_target_group_attachment",
+			ResourceID:      "id_test_alb_target_group_attachment",
+			SupportsImport:  false,
+		},
+		{
+			ResourceAddress: "aws_alb_target_group_attachment.multiline",
+			ResourceID:      "id\nrm -rf /",
+			SupportsImport:  false,
+		},
+	}
+
+	expectedResult := `terraform import 'aws_glue_catalog_database.test_db' 'id_test_db'
+terraform import 'module.x.aws_iam_role.z["it'\''s"]' 'role $name'
+# resource 'aws_alb_target_group_attachment.test_alb_target_group_attachment' with identifier 'id_test_alb_target_group_attachment' does not support import operation. Kindly refer resource documentation for more info.
+# resource 'aws_alb_target_group_attachment.multiline' with identifier 'id
+# rm -rf /' does not support import operation. Kindly refer resource documentation for more info.
+`
+
+	require.Equal(t, expectedResult, imports.Commands())
+	actual, err := imports.Render(tfimportgen.FormatCommand)
+	require.NoError(t, err)
+	require.Equal(t, expectedResult, actual)
+}
+
+func TestImports_ShouldSerializeAsJson(t *testing.T) {
+	imports := tfimportgen.TerraformImports{
+		{
+			ResourceAddress: "aws_glue_catalog_database.test_db",
+			ResourceID:      "id_test_db",
+			SupportsImport:  true,
+		},
+		{
+			ResourceAddress: `module.new.aws_iam_role.this["a.b"]`,
+			ResourceID:      "role",
+			SupportsImport:  true,
+			SourceAddress:   `module.old.aws_iam_role.this["a.b"]`,
+			Provider:        "aws.us_east_1",
+		},
+		{
+			ResourceAddress: "module.lb.aws_alb_target_group_attachment.test[0]",
+			ResourceID:      "id_test_alb_target_group_attachment",
+			SupportsImport:  false,
+		},
+	}
+
+	expectedResult := `[
+  {
+    "address": "aws_glue_catalog_database.test_db",
+    "type": "aws_glue_catalog_database",
+    "module_path": "",
+    "instance_key": null,
+    "id": "id_test_db",
+    "supports_import": true
+  },
+  {
+    "address": "module.new.aws_iam_role.this[\"a.b\"]",
+    "source_address": "module.old.aws_iam_role.this[\"a.b\"]",
+    "type": "aws_iam_role",
+    "module_path": "module.new",
+    "instance_key": "a.b",
+    "provider": "aws.us_east_1",
+    "id": "role",
+    "supports_import": true
+  },
+  {
+    "address": "module.lb.aws_alb_target_group_attachment.test[0]",
+    "type": "aws_alb_target_group_attachment",
+    "module_path": "module.lb",
+    "instance_key": 0,
+    "id": "id_test_alb_target_group_attachment",
+    "supports_import": false,
+    "unsupported_reason": "resource type aws_alb_target_group_attachment does not support import operation"
+  }
+]`
+
+	actual, err := imports.Render(tfimportgen.FormatJSON)
+	require.NoError(t, err)
+	require.Equal(t, expectedResult, actual)
+	actualJson, err := json.Marshal(imports)
+	require.NoError(t, err)
+	require.JSONEq(t, expectedResult, string(actualJson))
+}
+
+func TestImports_ShouldSerializeAsEmptyJsonArrayWhenThereAreNoImports(t *testing.T) {
+	actual, err := tfimportgen.TerraformImports(nil).Render(tfimportgen.FormatJSON)
+
+	require.NoError(t, err)
+	require.Equal(t, "[]", actual)
+}
+
+func TestImport_ShouldSerializeProviderOfAliasedProviderConfiguration(t *testing.T) {
+	tfImport := tfimportgen.TerraformImport{
+		ResourceAddress: "aws_acm_certificate.cdn",
+		ResourceID:      "arn:aws:acm:us-east-1:123456789012:certificate/cdn",
+		SupportsImport:  true,
+		Provider:        "aws.us_east_1",
+	}
+
+	expectedResult := `import {
+  to       = aws_acm_certificate.cdn
+  id       = "arn:aws:acm:us-east-1:123456789012:certificate/cdn"
+  provider = aws.us_east_1
+}
+`
+
+	require.Equal(t, expectedResult, tfImport.String())
+}
+
+func TestImports_ShouldSerializeInstancesOfAResourceAsForEachImportBlocks(t *testing.T) {
+	imports := tfimportgen.TerraformImports{
+		{ResourceAddress: `aws_s3_bucket.this["logs"]`, ResourceID: "logs-bucket", SupportsImport: true},
+		{ResourceAddress: "aws_iam_role.single", ResourceID: "single", SupportsImport: true},
+		{ResourceAddress: "aws_instance.web[0]", ResourceID: "i-0", SupportsImport: true, Provider: "aws.eu"},
+		{ResourceAddress: `aws_s3_bucket.this["assets"]`, ResourceID: "assets-bucket", SupportsImport: true},
+		{ResourceAddress: "aws_instance.web[1]", ResourceID: "i-1", SupportsImport: true, Provider: "aws.eu"},
+		{ResourceAddress: `module.app["a"].aws_sqs_queue.q[0]`, ResourceID: "q-0", SupportsImport: true},
+		{ResourceAddress: "aws_alb_target_group_attachment.t[0]", ResourceID: "t-0", SupportsImport: false},
+		{ResourceAddress: "aws_alb_target_group_attachment.t[1]", ResourceID: "t-1", SupportsImport: false},
+	}
+
+	expectedResult := `import {
+  for_each = {
+    "logs"   = "logs-bucket"
+    "assets" = "assets-bucket"
+  }
+
+  to = aws_s3_bucket.this[each.key]
+  id = each.value
+}
+
+import {
+  to = aws_iam_role.single
+  id = "single"
+}
+
+import {
+  for_each = {
+    "0" = "i-0"
+    "1" = "i-1"
+  }
+
+  to       = aws_instance.web[tonumber(each.key)]
+  id       = each.value
+  provider = aws.eu
+}
+
+import {
+  to = module.app["a"].aws_sqs_queue.q[0]
+  id = "q-0"
+}
+
+# resource "aws_alb_target_group_attachment.t[0]" with identifier "t-0" does not support import operation. Kindly refer resource documentation for more info.
+
+# resource "aws_alb_target_group_attachment.t[1]" with identifier "t-1" does not support import operation. Kindly refer resource documentation for more info.
+
+`
+
+	require.Equal(t, expectedResult, imports.ForEachBlocks())
+	actual, err := imports.Render(tfimportgen.FormatForEach)
+	require.NoError(t, err)
+	require.Equal(t, expectedResult, actual)
+}
+
+func TestImports_ShouldCommentOutImportsWithDiagnostics(t *testing.T) {
+	imports := tfimportgen.TerraformImports{
+		{
+			ResourceAddress: "aws_iam_role_policy_attachment.test",
+			SupportsImport:  true,
+			Diagnostics: []tfimportgen.Diagnostic{
+				{Kind: tfimportgen.DiagnosticMissingAttribute, Address: "aws_iam_role_policy_attachment.test", Attribute: "policy_arn"},
+				{Kind: tfimportgen.DiagnosticEmptyValue, Address: "aws_iam_role_policy_attachment.test", Attribute: "role"},
+			},
+		},
+	}
+
+	expectedResult := `# warning: attribute "policy_arn" required for the import id of aws_iam_role_policy_attachment.test is missing
+# warning: attribute "role" required for the import id of aws_iam_role_policy_attachment.test is empty
+# import {
+#   to = aws_iam_role_policy_attachment.test
+#   id = ""
+# }
+
+`
+	expectedCommands := `# warning: attribute "policy_arn" required for the import id of aws_iam_role_policy_attachment.test is missing
+# warning: attribute "role" required for the import id of aws_iam_role_policy_attachment.test is empty
+# terraform import 'aws_iam_role_policy_attachment.test' ''
+`
+
+	require.Equal(t, expectedResult, imports.String())
+	require.Equal(t, expectedCommands, imports.Commands())
+	require.Equal(t, imports[0].Diagnostics, imports.Diagnostics())
+}
+
+func TestImports_ShouldSerializeAsImportBlocksUsingResourceIdentity(t *testing.T) {
+	imports := tfimportgen.TerraformImports{
+		{
+			ResourceAddress: "aws_instance.web",
+			ResourceID:      "i-1",
+			SupportsImport:  true,
+			Identity:        map[string]any{"region": "eu-west-1", "id": "i-1", "account_id": "123456789012", "unset": nil},
+		},
+		{
+			ResourceAddress: "aws_iam_role_policy_attachment.test",
+			SupportsImport:  true,
+			Provider:        "aws.us_east_1",
+			Diagnostics: []tfimportgen.Diagnostic{
+				{Kind: tfimportgen.DiagnosticMissingAttribute, Address: "aws_iam_role_policy_attachment.test", Attribute: "role"},
+			},
+			Identity: map[string]any{"role": "test-role", "policy_arn": "arn:aws:iam::aws:policy/ReadOnlyAccess"},
+		},
+		{
+			ResourceAddress: "aws_glue_catalog_database.test_db",
+			ResourceID:      "id_test_db",
+			SupportsImport:  true,
+		},
+	}
+
+	expectedResult := `import {
+  to = aws_instance.web
+
+  identity = {
+    account_id = "123456789012"
+    id         = "i-1"
+    region     = "eu-west-1"
+  }
+}
+
+import {
+  to       = aws_iam_role_policy_attachment.test
+  provider = aws.us_east_1
+
+  identity = {
+    policy_arn = "arn:aws:iam::aws:policy/ReadOnlyAccess"
+    role       = "test-role"
+  }
+}
+
+import {
+  to = aws_glue_catalog_database.test_db
+  id = "id_test_db"
+}
+
+`
+
+	require.Equal(t, expectedResult, imports.IdentityBlocks())
+	actual, err := imports.Render(tfimportgen.FormatIdentity)
+	require.NoError(t, err)
+	require.Equal(t, expectedResult, actual)
+}
@@ -0,0 +1,214 @@
+package parser
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Address is a module address like module.a["x"].module.b or an absolute
+// resource (instance) address like module.a["x"].aws_s3_bucket.b[0]
+type Address struct {
+	Module []ModuleInstance
+	Mode   string
+	Type   string
+	Name   string
+	// Key is the instance key of the resource, an int or a string. It is nil
+	// for resources without count or for_each and for addresses which refer to
+	// all the instances of a resource.
+	Key any
+}
+
+type ModuleInstance struct {
+	Name string
+	// Key is the instance key of the module, an int or a string. It is nil for
+	// modules without count or for_each and for addresses which refer to all
+	// the instances of a module.
+	Key any
+}
+
+// ParseAddress parses a module or resource address. An empty string is parsed
+// as the address of the root module.
+func ParseAddress(address string) (Address, error) {
+	var parsedAddress Address
+	if len(address) == 0 {
+		return parsedAddress, nil
+	}
+	segments, err := splitAddressSegments(address)
+	if err != nil {
+		return Address{}, fmt.Errorf("invalid address %q: %w", address, err)
+	}
+	for len(segments) >= 2 && segments[0].name == "module" && segments[0].key == nil {
+		parsedAddress.Module = append(parsedAddress.Module, ModuleInstance{Name: segments[1].name, Key: segments[1].key})
+		segments = segments[2:]
+	}
+	parsedAddress.Mode = "managed"
+	if len(segments) == 3 && segments[0].name == "data" && segments[0].key == nil {
+		parsedAddress.Mode = "data"
+		segments = segments[1:]
+	}
+	switch {
+	case len(segments) == 0:
+		parsedAddress.Mode = ""
+		return parsedAddress, nil
+	case len(segments) == 2 && segments[0].key == nil:
+		parsedAddress.Type = segments[0].name
+		parsedAddress.Name = segments[1].name
+		parsedAddress.Key = segments[1].key
+		return parsedAddress, nil
+	default:
+		return Address{}, fmt.Errorf("invalid address %q: expected module path followed by resource type and name", address)
+	}
+}
+
+// IsModule reports whether the address refers to a module rather than a resource
+func (address Address) IsModule() bool {
+	return len(address.Type) == 0
+}
+
+// Contains reports whether the resource instance is selected by the address,
+// the same way `terraform state list <address>` selects resources. Module
+// addresses select all the resources within the module and its child modules
+// and addresses without instance keys select all the instances. The module
+// names, resource types and resource names of the address may be glob
+// patterns using * and ?, e.g. module.*.aws_iam_*.*
+func (address Address) Contains(resourceInstance Address) bool {
+	if len(address.Module) > len(resourceInstance.Module) {
+		return false
+	}
+	for i, moduleInstance := range address.Module {
+		if !nameContains(moduleInstance.Name, resourceInstance.Module[i].Name) || !keyContains(moduleInstance.Key, resourceInstance.Module[i].Key) {
+			return false
+		}
+	}
+	if address.IsModule() {
+		return true
+	}
+	return len(address.Module) == len(resourceInstance.Module) &&
+		address.Mode == resourceInstance.Mode &&
+		nameContains(address.Type, resourceInstance.Type) &&
+		nameContains(address.Name, resourceInstance.Name) &&
+		keyContains(address.Key, resourceInstance.Key)
+}
+
+func nameContains(namePattern string, name string) bool {
+	matched, err := path.Match(namePattern, name)
+	return err == nil && matched
+}
+
+func keyContains(key any, instanceKey any) bool {
+	return key == nil || key == instanceKey
+}
+
+// ModulePath returns the module part of the address, e.g. module.a["x"].module.b
+func (address Address) ModulePath() string {
+	var modulePath []string
+	for _, moduleInstance := range address.Module {
+		modulePath = append(modulePath, fmt.Sprintf("module.%s%s", moduleInstance.Name, computeIndexSuffix(moduleInstance.Key)))
+	}
+	return strings.Join(modulePath, ".")
+}
+
+// WithoutKeys returns the address with all module and resource instance keys removed
+func (address Address) WithoutKeys() Address {
+	var module []ModuleInstance
+	for _, moduleInstance := range address.Module {
+		module = append(module, ModuleInstance{Name: moduleInstance.Name})
+	}
+	return Address{
+		Module: module,
+		Mode:   address.Mode,
+		Type:   address.Type,
+		Name:   address.Name,
+	}
+}
+
+func (address Address) String() string {
+	modulePath := address.ModulePath()
+	if address.IsModule() {
+		return modulePath
+	}
+	resourceAddress := fmt.Sprintf("%s.%s%s", address.Type, address.Name, computeIndexSuffix(address.Key))
+	if address.Mode == "data" {
+		resourceAddress = "data." + resourceAddress
+	}
+	if len(modulePath) == 0 {
+		return resourceAddress
+	}
+	return fmt.Sprintf("%s.%s", modulePath, resourceAddress)
+}
+
+type addressSegment struct {
+	name string
+	key  any
+}
+
+// splitAddressSegments splits an address on the dots which are not part of an
+// instance key and separates the instance keys from the names
+func splitAddressSegments(address string) ([]addressSegment, error) {
+	var segments []addressSegment
+	for len(address) > 0 {
+		nameEnd := strings.IndexAny(address, ".[")
+		if nameEnd == -1 {
+			nameEnd = len(address)
+		}
+		segment := addressSegment{name: address[:nameEnd]}
+		if len(segment.name) == 0 {
+			return nil, fmt.Errorf("empty name at %q", address)
+		}
+		address = address[nameEnd:]
+		if strings.HasPrefix(address, "[") {
+			key, rest, err := parseInstanceKey(address)
+			if err != nil {
+				return nil, err
+			}
+			segment.key = key
+			address = rest
+		}
+		segments = append(segments, segment)
+		if len(address) == 0 {
+			break
+		}
+		if !strings.HasPrefix(address, ".") || len(address) == 1 {
+			return nil, fmt.Errorf("unexpected %q", address)
+		}
+		address = address[1:]
+	}
+	return segments, nil
+}
+
+// parseInstanceKey parses a leading instance key like ["key"] or [0] and
+// returns the key and the remainder of the address
+func parseInstanceKey(address string) (any, string, error) {
+	if strings.HasPrefix(address, `["`) {
+		escaped := false
+		for i := 2; i < len(address); i++ {
+			switch {
+			case escaped:
+				escaped = false
+			case address[i] == '\\':
+				escaped = true
+			case address[i] == '"':
+				if i+1 >= len(address) || address[i+1] != ']' {
+					return nil, "", fmt.Errorf("unterminated instance key %q", address)
+				}
+				key, err := unquoteHCLString(address[1 : i+1])
+				if err != nil {
+					return nil, "", fmt.Errorf("invalid instance key %q: %w", address[:i+2], err)
+				}
+				return key, address[i+2:], nil
+			}
+		}
+		return nil, "", fmt.Errorf("unterminated instance key %q", address)
+	}
+	keyEnd := strings.Index(address, "]")
+	if keyEnd == -1 {
+		return nil, "", fmt.Errorf("unterminated instance key %q", address)
+	}
+	key, err := strconv.Atoi(address[1:keyEnd])
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid instance key %q, expected a number or a quoted string", address[:keyEnd+1])
+	}
+	return key, address[keyEnd+1:], nil
+}
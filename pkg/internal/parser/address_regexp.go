@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// AddressRegexp selects resources using a regular expression per address
+// segment, e.g. module.team_.*.aws_iam_(role|policy).* The pattern is split
+// on the dots which are not escaped, not within a group or character class
+// and not followed by a quantifier, so that .* keeps its meaning within a
+// segment. Each segment regexp must match the whole name of the corresponding
+// address segment, or the name including its instance key. Like module
+// addresses, a pattern with fewer segments selects everything below it.
+type AddressRegexp struct {
+	segments []*regexp.Regexp
+}
+
+func CompileAddressRegexp(pattern string) (AddressRegexp, error) {
+	var addressRegexp AddressRegexp
+	if len(pattern) == 0 {
+		return addressRegexp, nil
+	}
+	for _, segmentPattern := range splitRegexpSegments(pattern) {
+		segment, err := regexp.Compile(fmt.Sprintf("^(?:%s)$", segmentPattern))
+		if err != nil {
+			return AddressRegexp{}, fmt.Errorf("invalid address regexp %q: %w", pattern, err)
+		}
+		addressRegexp.segments = append(addressRegexp.segments, segment)
+	}
+	return addressRegexp, nil
+}
+
+// MatchString reports whether the resource instance address is selected by the regexp
+func (addressRegexp AddressRegexp) MatchString(resourceInstanceAddress string) bool {
+	resourceSegments, err := splitAddressSegments(resourceInstanceAddress)
+	if err != nil || len(addressRegexp.segments) > len(resourceSegments) {
+		return false
+	}
+	for i, segment := range addressRegexp.segments {
+		resourceSegment := resourceSegments[i]
+		if !segment.MatchString(resourceSegment.name) && !segment.MatchString(resourceSegment.name+computeIndexSuffix(resourceSegment.key)) {
+			return false
+		}
+	}
+	return true
+}
+
+func splitRegexpSegments(pattern string) []string {
+	var segments []string
+	var segment strings.Builder
+	escaped, inCharacterClass, groupDepth := false, false, 0
+	for i := 0; i < len(pattern); i++ {
+		char := pattern[i]
+		switch {
+		case escaped:
+			escaped = false
+		case char == '\\':
+			escaped = true
+		case inCharacterClass:
+			inCharacterClass = char != ']'
+		case char == '[':
+			inCharacterClass = true
+		case char == '(':
+			groupDepth++
+		case char == ')':
+			groupDepth--
+		case char == '.' && groupDepth == 0 && (i+1 == len(pattern) || !strings.ContainsRune("*+?{", rune(pattern[i+1]))):
+			segments = append(segments, segment.String())
+			segment.Reset()
+			continue
+		}
+		segment.WriteByte(char)
+	}
+	return append(segments, segment.String())
+}
@@ -0,0 +1,38 @@
+package parser
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestAddressRegexpMatchString(t *testing.T) {
+	tests := []struct {
+		pattern          string
+		resourceInstance string
+		expected         bool
+	}{
+		{pattern: "", resourceInstance: "aws_iam_role.this", expected: true},
+		{pattern: "aws_iam_.*", resourceInstance: "aws_iam_role.this", expected: true},
+		{pattern: "aws_iam_.*", resourceInstance: "module.app.aws_iam_role.this", expected: false},
+		{pattern: "module.team_.*.aws_iam_(role|policy)", resourceInstance: `module.team_a.aws_iam_role.this["x"]`, expected: true},
+		{pattern: "module.team_.*.aws_iam_(role|policy)", resourceInstance: "module.team_a.aws_iam_user.this", expected: false},
+		{pattern: "module.team_[a-c].aws_s3_bucket.this", resourceInstance: "module.team_b.aws_s3_bucket.this[0]", expected: true},
+		{pattern: "module.team_[a-c].aws_s3_bucket.this", resourceInstance: "module.team_d.aws_s3_bucket.this[0]", expected: false},
+		{pattern: `aws_s3_bucket.this\[[0-2]\]`, resourceInstance: "aws_s3_bucket.this[1]", expected: true},
+		{pattern: `aws_s3_bucket.this\[[0-2]\]`, resourceInstance: "aws_s3_bucket.this[3]", expected: false},
+		{pattern: "module.app", resourceInstance: "module.application.aws_s3_bucket.this", expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" matches "+tt.resourceInstance, func(t *testing.T) {
+			addressRegexp, err := CompileAddressRegexp(tt.pattern)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, addressRegexp.MatchString(tt.resourceInstance))
+		})
+	}
+}
+
+func TestCompileAddressRegexpRejectsInvalidPatterns(t *testing.T) {
+	_, err := CompileAddressRegexp("module.team_(")
+
+	require.EqualError(t, err, "invalid address regexp \"module.team_(\": error parsing regexp: missing closing ): `^(?:team_()$`")
+}
@@ -0,0 +1,163 @@
+package parser
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		address  string
+		expected Address
+	}{
+		{
+			address:  "",
+			expected: Address{},
+		},
+		{
+			address:  "module.app",
+			expected: Address{Module: []ModuleInstance{{Name: "app"}}},
+		},
+		{
+			address:  `module.app["a.b"].module.child[0]`,
+			expected: Address{Module: []ModuleInstance{{Name: "app", Key: "a.b"}, {Name: "child", Key: 0}}},
+		},
+		{
+			address:  "aws_instance.web",
+			expected: Address{Mode: "managed", Type: "aws_instance", Name: "web"},
+		},
+		{
+			address:  `module.app.aws_instance.web["it's \"quoted\" ]"]`,
+			expected: Address{Module: []ModuleInstance{{Name: "app"}}, Mode: "managed", Type: "aws_instance", Name: "web", Key: `it's "quoted" ]`},
+		},
+		{
+			address:  `aws_iam_role.this["$${team} %%{dir} \\ \n"]`,
+			expected: Address{Mode: "managed", Type: "aws_iam_role", Name: "this", Key: "${team} %{dir} \\ \n"},
+		},
+		{
+			address:  "module.app.data.aws_caller_identity.current",
+			expected: Address{Module: []ModuleInstance{{Name: "app"}}, Mode: "data", Type: "aws_caller_identity", Name: "current"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			actual, err := ParseAddress(tt.address)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, actual)
+			require.Equal(t, tt.address, actual.String())
+		})
+	}
+}
+
+func TestParseAddressRejectsInvalidAddresses(t *testing.T) {
+	tests := []struct {
+		address       string
+		expectedError string
+	}{
+		{
+			address:       "aws_instance",
+			expectedError: `invalid address "aws_instance": expected module path followed by resource type and name`,
+		},
+		{
+			address:       "module.app.aws_instance.web.extra",
+			expectedError: `invalid address "module.app.aws_instance.web.extra": expected module path followed by resource type and name`,
+		},
+		{
+			address:       "aws_instance.web[",
+			expectedError: `invalid address "aws_instance.web[": unterminated instance key "["`,
+		},
+		{
+			address:       "aws_instance.web[one]",
+			expectedError: `invalid address "aws_instance.web[one]": invalid instance key "[one]", expected a number or a quoted string`,
+		},
+		{
+			address:       "aws_instance..web",
+			expectedError: `invalid address "aws_instance..web": empty name at ".web"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			_, err := ParseAddress(tt.address)
+			require.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestAddressContains(t *testing.T) {
+	tests := []struct {
+		address          string
+		resourceInstance string
+		expected         bool
+	}{
+		{address: "", resourceInstance: `module.app["a"].aws_instance.web[0]`, expected: true},
+		{address: "aws_instance.web", resourceInstance: "aws_instance.web", expected: true},
+		{address: "aws_instance.web", resourceInstance: "aws_instance.web[0]", expected: true},
+		{address: "aws_instance.web", resourceInstance: "aws_instance.web_backup", expected: false},
+		{address: "aws_instance.web", resourceInstance: "module.app.aws_instance.web", expected: false},
+		{address: "aws_instance.web[0]", resourceInstance: "aws_instance.web[0]", expected: true},
+		{address: "aws_instance.web[0]", resourceInstance: "aws_instance.web[1]", expected: false},
+		{address: `aws_instance.web["0"]`, resourceInstance: "aws_instance.web[0]", expected: false},
+		{address: "module.app", resourceInstance: "module.app.aws_instance.web", expected: true},
+		{address: "module.app", resourceInstance: `module.app["a"].module.child.aws_instance.web`, expected: true},
+		{address: "module.app", resourceInstance: "module.application.aws_instance.web", expected: false},
+		{address: `module.app["a"]`, resourceInstance: `module.app["a"].aws_instance.web`, expected: true},
+		{address: `module.app["a"]`, resourceInstance: `module.app["b"].aws_instance.web`, expected: false},
+		{address: "module.app.aws_instance.web", resourceInstance: `module.app["a"].aws_instance.web`, expected: true},
+		{address: "module.app.module.child", resourceInstance: "module.app.aws_instance.web", expected: false},
+		{address: "module.*.aws_iam_role.*", resourceInstance: `module.team_a.aws_iam_role.this["x"]`, expected: true},
+		{address: "module.*.aws_iam_role.*", resourceInstance: "aws_iam_role.this", expected: false},
+		{address: "module.team_*.aws_iam_*.*", resourceInstance: "module.team_a.aws_iam_policy.this", expected: true},
+		{address: "module.team_*.aws_iam_*.*", resourceInstance: "module.platform.aws_iam_policy.this", expected: false},
+		{address: "module.team_?", resourceInstance: "module.team_b.module.child.aws_s3_bucket.this", expected: true},
+		{address: "module.team_?", resourceInstance: "module.team_bc.aws_s3_bucket.this", expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address+" contains "+tt.resourceInstance, func(t *testing.T) {
+			address, err := ParseAddress(tt.address)
+			require.NoError(t, err)
+			resourceInstance, err := ParseAddress(tt.resourceInstance)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, address.Contains(resourceInstance))
+		})
+	}
+}
+
+func TestTerraformResourcesFilterByAddresses(t *testing.T) {
+	resources := TerraformResources{
+		{Address: "aws_instance.web"},
+		{Address: "aws_instance.web_backup"},
+		{Address: "module.app.aws_instance.web[0]"},
+		{Address: "module.application.aws_instance.web[0]"},
+	}
+
+	actual, err := resources.FilterByAddresses([]string{"aws_instance.web", "module.app"})
+
+	require.NoError(t, err)
+	require.Equal(t, TerraformResources{
+		{Address: "aws_instance.web"},
+		{Address: "module.app.aws_instance.web[0]"},
+	}, actual)
+
+	_, err = resources.FilterByAddresses([]string{"aws_instance"})
+	require.EqualError(t, err, `invalid address "aws_instance": expected module path followed by resource type and name`)
+}
+
+func TestTerraformResourcesExclusions(t *testing.T) {
+	resources := TerraformResources{
+		{Address: "aws_instance.web", Type: "aws_instance"},
+		{Address: "module.app.aws_iam_policy_attachment.this", Type: "aws_iam_policy_attachment"},
+		{Address: "module.app.aws_iam_role.this", Type: "aws_iam_role"},
+		{Address: "module.app.aws_s3_bucket.logs", Type: "aws_s3_bucket"},
+	}
+
+	actual, err := resources.ExcludeByAddresses([]string{"module.app.aws_s3_bucket.logs"})
+	require.NoError(t, err)
+	require.Equal(t, resources[:3], actual)
+
+	actual, err = resources.ExcludeByAddressRegexps([]string{"module.app.aws_iam_.*"})
+	require.NoError(t, err)
+	require.Equal(t, TerraformResources{resources[0], resources[3]}, actual)
+
+	require.Equal(t, TerraformResources{resources[0], resources[2], resources[3]}, resources.ExcludeByTypes([]string{"aws_iam_policy_attachment"}))
+	require.Equal(t, TerraformResources{resources[0], resources[3]}, resources.ExcludeByTypes([]string{"aws_iam_*"}))
+}
@@ -0,0 +1,35 @@
+package parser
+
+import (
+	"errors"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// quoteHCLString quotes the value like terraform quotes string instance keys.
+// Unlike go, HCL also escapes the template sequences ${ and %{.
+func quoteHCLString(value string) string {
+	return string(hclwrite.TokensForValue(cty.StringVal(value)).Bytes())
+}
+
+// unquoteHCLString is the inverse of quoteHCLString
+func unquoteHCLString(quoted string) (string, error) {
+	expression, diagnostics := hclsyntax.ParseExpression([]byte(quoted), "", hcl.InitialPos)
+	if diagnostics.HasErrors() {
+		return "", diagnostics
+	}
+	if _, ok := expression.(*hclsyntax.TemplateExpr); !ok {
+		return "", errors.New("not a quoted string")
+	}
+	value, diagnostics := expression.Value(nil)
+	if diagnostics.HasErrors() {
+		return "", diagnostics
+	}
+	if value.IsNull() || !value.IsKnown() || value.Type() != cty.String {
+		return "", errors.New("not a quoted string")
+	}
+	return value.AsString(), nil
+}
@@ -32,6 +32,9 @@ func (parser TerraformStateJsonParser) Parse() (TerraformResources, error) {
 	if err != nil {
 		return nil, err
 	}
+	if state.Values == nil || state.Values.RootModule == nil {
+		return nil, nil
+	}
 	return parser.parseResourcesWithinModule(state.Values.RootModule), nil
 }
 
@@ -47,18 +50,31 @@ func (parser TerraformStateJsonParser) parseResourcesWithinModule(module *tfjson
 func (parser TerraformStateJsonParser) parseResources(resources []*tfjson.StateResource, moduleAddress string) []TerraformResource {
 	var resourceImportModel []TerraformResource
 	for _, resource := range resources {
-		if resource.Mode != tfjson.ManagedResourceMode {
+		// deposed objects share the address of the current object
+		if resource.Mode != tfjson.ManagedResourceMode || len(resource.DeposedKey) > 0 {
 			continue
 		}
 		resourceImportModel = append(resourceImportModel, TerraformResource{
 			Address:         parser.computeResourceAddressIncludingModule(moduleAddress, resource),
 			Type:            resource.Type,
+			Index:           resource.Index,
 			AttributeValues: resource.AttributeValues,
+			SensitiveValues: parser.parseSensitiveValues(resource.SensitiveValues),
+			Provider:        TerraformProvider{Name: resource.ProviderName},
+			Identity:        resource.IdentityValues,
 		})
 	}
 	return resourceImportModel
 }
 
+func (parser TerraformStateJsonParser) parseSensitiveValues(sensitiveValues json.RawMessage) map[string]any {
+	var parsedSensitiveValues map[string]any
+	if err := json.Unmarshal(sensitiveValues, &parsedSensitiveValues); err != nil || len(parsedSensitiveValues) == 0 {
+		return nil
+	}
+	return parsedSensitiveValues
+}
+
 func (parser TerraformStateJsonParser) computeResourceAddressIncludingModule(moduleAddress string, resource *tfjson.StateResource) string {
 	resourceAddress := parser.computeResourceAddress(resource)
 	if len(moduleAddress) == 0 {
@@ -72,12 +88,7 @@ func (parser TerraformStateJsonParser) computeResourceAddressIncludingModule(mod
 
 func (parser TerraformStateJsonParser) computeResourceAddress(resource *tfjson.StateResource) string {
 	if resource.Index != nil && !strings.HasSuffix(resource.Address, "]") {
-		switch resource.Index.(type) {
-		case float64, float32, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
-			return fmt.Sprintf("%s[%v]", resource.Address, resource.Index)
-		default:
-			return fmt.Sprintf("%s[%q]", resource.Address, resource.Index)
-		}
+		return resource.Address + computeIndexSuffix(resource.Index)
 	}
 	return resource.Address
 }
@@ -1,7 +1,12 @@
 package parser
 
 import (
-	"strings"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
 )
 
 type TerraformResource struct {
@@ -9,23 +14,204 @@ type TerraformResource struct {
 	Type            string
 	Index           any
 	AttributeValues map[string]any
+	// SensitiveValues mirrors AttributeValues with true for the values which are
+	// sensitive, like the sensitive_values of `terraform show -json`. It is nil
+	// when no value is sensitive.
+	SensitiveValues map[string]any
+	Provider        TerraformProvider
+	// Identity is the resource identity which terraform 1.12 or later records
+	// for the resources of providers supporting it. It is nil otherwise.
+	Identity map[string]any
+	// PlannedAction is only populated when the resources are parsed from a plan
+	PlannedAction PlannedAction
+}
+
+type PlannedAction string
+
+const (
+	PlannedActionUnknown PlannedAction = ""
+	PlannedActionNoOp    PlannedAction = "no-op"
+	PlannedActionUpdate  PlannedAction = "update"
+	PlannedActionDelete  PlannedAction = "delete"
+	PlannedActionReplace PlannedAction = "replace"
+	PlannedActionForget  PlannedAction = "forget"
+)
+
+// PlannedActions are the actions which can be used to select resources from a plan
+var PlannedActions = []PlannedAction{
+	PlannedActionNoOp,
+	PlannedActionUpdate,
+	PlannedActionDelete,
+	PlannedActionReplace,
+	PlannedActionForget,
 }
 
 type TerraformResources []TerraformResource
 
-func (resources TerraformResources) FilterByAddresses(addresses []string) TerraformResources {
+// FilterByAddresses selects the resources which are contained in any of the
+// given module or resource addresses, see Address.Contains
+func (resources TerraformResources) FilterByAddresses(addresses []string) (TerraformResources, error) {
+	containedInAddresses, err := newAddressesMatcher(addresses)
+	if err != nil {
+		return nil, err
+	}
+	return resources.filter(containedInAddresses), nil
+}
+
+// ExcludeByAddresses drops the resources which are contained in any of the
+// given module or resource addresses, see Address.Contains
+func (resources TerraformResources) ExcludeByAddresses(addresses []string) (TerraformResources, error) {
+	containedInAddresses, err := newAddressesMatcher(addresses)
+	if err != nil {
+		return nil, err
+	}
+	return resources.filter(not(containedInAddresses)), nil
+}
+
+// FilterByAddressRegexps selects the resources which match any of the given
+// patterns, see AddressRegexp
+func (resources TerraformResources) FilterByAddressRegexps(patterns []string) (TerraformResources, error) {
+	matchesAddressRegexps, err := newAddressRegexpsMatcher(patterns)
+	if err != nil {
+		return nil, err
+	}
+	return resources.filter(matchesAddressRegexps), nil
+}
+
+// ExcludeByAddressRegexps drops the resources which match any of the given
+// patterns, see AddressRegexp
+func (resources TerraformResources) ExcludeByAddressRegexps(patterns []string) (TerraformResources, error) {
+	matchesAddressRegexps, err := newAddressRegexpsMatcher(patterns)
+	if err != nil {
+		return nil, err
+	}
+	return resources.filter(not(matchesAddressRegexps)), nil
+}
+
+// ExcludeByTypes drops the resources whose type matches any of the given
+// types, which may be glob patterns like aws_iam_*
+func (resources TerraformResources) ExcludeByTypes(types []string) TerraformResources {
+	return resources.filter(func(resource TerraformResource) bool {
+		return !slices.ContainsFunc(types, func(resourceType string) bool {
+			return nameContains(resourceType, resource.Type)
+		})
+	})
+}
+
+// FilterByProviders selects the resources managed by any of the given
+// providers, see TerraformProvider.Matches
+func (resources TerraformResources) FilterByProviders(providers []string) TerraformResources {
+	return resources.filter(func(resource TerraformResource) bool {
+		return slices.ContainsFunc(providers, resource.Provider.Matches)
+	})
+}
+
+type resourceMatcher func(resource TerraformResource) bool
+
+func not(matcher resourceMatcher) resourceMatcher {
+	return func(resource TerraformResource) bool {
+		return !matcher(resource)
+	}
+}
+
+func newAddressesMatcher(addresses []string) (resourceMatcher, error) {
+	var filterAddresses []Address
+	for _, address := range addresses {
+		filterAddress, err := ParseAddress(address)
+		if err != nil {
+			return nil, err
+		}
+		filterAddresses = append(filterAddresses, filterAddress)
+	}
+	return func(resource TerraformResource) bool {
+		resourceAddress, err := ParseAddress(resource.Address)
+		for _, filterAddress := range filterAddresses {
+			// the root module contains every resource, even when its address cannot be parsed
+			isRootModule := filterAddress.IsModule() && len(filterAddress.Module) == 0
+			if isRootModule || (err == nil && filterAddress.Contains(resourceAddress)) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func newAddressRegexpsMatcher(patterns []string) (resourceMatcher, error) {
+	var addressRegexps []AddressRegexp
+	for _, pattern := range patterns {
+		addressRegexp, err := CompileAddressRegexp(pattern)
+		if err != nil {
+			return nil, err
+		}
+		addressRegexps = append(addressRegexps, addressRegexp)
+	}
+	return func(resource TerraformResource) bool {
+		for _, addressRegexp := range addressRegexps {
+			if addressRegexp.MatchString(resource.Address) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func (resources TerraformResources) filter(matches resourceMatcher) TerraformResources {
 	var filteredResources TerraformResources
 	for _, resource := range resources {
-		for _, address := range addresses {
-			if strings.HasPrefix(resource.Address, address) {
-				filteredResources = append(filteredResources, resource)
-				break
-			}
+		if matches(resource) {
+			filteredResources = append(filteredResources, resource)
 		}
 	}
 	return filteredResources
 }
 
+func (resources TerraformResources) FilterByPlannedActions(plannedActions []PlannedAction) TerraformResources {
+	return resources.filter(func(resource TerraformResource) bool {
+		return slices.Contains(plannedActions, resource.PlannedAction)
+	})
+}
+
 type TerraformStateParser interface {
 	Parse() (TerraformResources, error)
 }
+
+// NewTerraformStateParser detects whether the given input is the output of
+// `terraform show -json` for a state or a plan, or a raw state file and
+// returns the matching parser.
+func NewTerraformStateParser(reader io.Reader) (TerraformStateParser, error) {
+	inputBytes, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	var header struct {
+		FormatVersion   string          `json:"format_version"`
+		Version         *int            `json:"version"`
+		PlannedValues   json.RawMessage `json:"planned_values"`
+		ResourceChanges json.RawMessage `json:"resource_changes"`
+	}
+	err = json.Unmarshal(inputBytes, &header)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(header.FormatVersion) > 0 && (header.PlannedValues != nil || header.ResourceChanges != nil):
+		return NewTerraformPlanJsonParser(bytes.NewReader(inputBytes)), nil
+	case len(header.FormatVersion) > 0:
+		return NewTerraformStateJsonParser(bytes.NewReader(inputBytes)), nil
+	case header.Version != nil:
+		return NewTerraformStateFileParser(bytes.NewReader(inputBytes)), nil
+	default:
+		return nil, errors.New("unrecognized input, expected the output of `terraform show -json` for a state or a plan, or a terraform state file")
+	}
+}
+
+func computeIndexSuffix(index any) string {
+	switch index.(type) {
+	case nil:
+		return ""
+	case float64, float32, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("[%v]", index)
+	default:
+		return fmt.Sprintf("[%s]", quoteHCLString(fmt.Sprint(index)))
+	}
+}
@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"encoding/json"
+	tfjson "github.com/hashicorp/terraform-json"
+	"io"
+)
+
+var (
+	_ TerraformStateParser = TerraformPlanJsonParser{}
+)
+
+// TerraformPlanJsonParser parses the output of `terraform show -json <planfile>`.
+// The resources are taken from the prior state of the plan and are annotated
+// with the action the plan intends to take on them.
+type TerraformPlanJsonParser struct {
+	reader io.Reader
+}
+
+func NewTerraformPlanJsonParser(reader io.Reader) TerraformStateParser {
+	return TerraformPlanJsonParser{
+		reader: reader,
+	}
+}
+
+func (parser TerraformPlanJsonParser) Parse() (TerraformResources, error) {
+	tfPlanJsonBytes, err := io.ReadAll(parser.reader)
+	if err != nil {
+		return nil, err
+	}
+	var plan tfjson.Plan
+	err = json.Unmarshal(tfPlanJsonBytes, &plan)
+	if err != nil {
+		return nil, err
+	}
+	if plan.PriorState == nil || plan.PriorState.Values == nil || plan.PriorState.Values.RootModule == nil {
+		return nil, nil
+	}
+	resources := TerraformStateJsonParser{}.parseResourcesWithinModule(plan.PriorState.Values.RootModule)
+	plannedActions := parser.computePlannedActions(plan.ResourceChanges)
+	for i, resource := range resources {
+		resources[i].PlannedAction = plannedActions[resource.Address]
+	}
+	return resources, nil
+}
+
+func (parser TerraformPlanJsonParser) computePlannedActions(resourceChanges []*tfjson.ResourceChange) map[string]PlannedAction {
+	plannedActions := make(map[string]PlannedAction)
+	for _, resourceChange := range resourceChanges {
+		if resourceChange.Mode != tfjson.ManagedResourceMode || resourceChange.Change == nil || len(resourceChange.DeposedKey) > 0 {
+			continue
+		}
+		plannedAction := parser.computePlannedAction(resourceChange.Change.Actions)
+		plannedActions[resourceChange.Address] = plannedAction
+		if len(resourceChange.PreviousAddress) > 0 {
+			plannedActions[resourceChange.PreviousAddress] = plannedAction
+		}
+	}
+	return plannedActions
+}
+
+func (parser TerraformPlanJsonParser) computePlannedAction(actions tfjson.Actions) PlannedAction {
+	switch {
+	case actions.Replace():
+		return PlannedActionReplace
+	case actions.Delete():
+		return PlannedActionDelete
+	case actions.Update():
+		return PlannedActionUpdate
+	case actions.Forget():
+		return PlannedActionForget
+	case actions.NoOp():
+		return PlannedActionNoOp
+	default:
+		return PlannedActionUnknown
+	}
+}
@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestTerraformPlanJsonParserAnnotatesPriorStateWithPlannedActions(t *testing.T) {
+	inputTerraformPlanJson := `
+		{
+		  "format_version": "1.2",
+		  "resource_changes": [
+			{
+			  "address": "aws_glue_catalog_database.kept",
+			  "mode": "managed",
+			  "type": "aws_glue_catalog_database",
+			  "name": "kept",
+			  "change": {"actions": ["no-op"]}
+			},
+			{
+			  "address": "aws_glue_catalog_database.replaced[0]",
+			  "mode": "managed",
+			  "type": "aws_glue_catalog_database",
+			  "name": "replaced",
+			  "index": 0,
+			  "change": {"actions": ["create", "delete"]}
+			},
+			{
+			  "address": "aws_glue_catalog_database.renamed",
+			  "previous_address": "aws_glue_catalog_database.old_name",
+			  "mode": "managed",
+			  "type": "aws_glue_catalog_database",
+			  "name": "renamed",
+			  "change": {"actions": ["delete"]}
+			}
+		  ],
+		  "prior_state": {
+			"format_version": "1.0",
+			"values": {
+			  "root_module": {
+				"resources": [
+				  {
+					"address": "aws_glue_catalog_database.kept",
+					"mode": "managed",
+					"type": "aws_glue_catalog_database",
+					"name": "kept",
+					"values": {"id": "kept"}
+				  },
+				  {
+					"address": "aws_glue_catalog_database.replaced[0]",
+					"mode": "managed",
+					"type": "aws_glue_catalog_database",
+					"name": "replaced",
+					"index": 0,
+					"values": {"id": "replaced"}
+				  },
+				  {
+					"address": "aws_glue_catalog_database.old_name",
+					"mode": "managed",
+					"type": "aws_glue_catalog_database",
+					"name": "old_name",
+					"values": {"id": "old_name"}
+				  }
+				]
+			  }
+			}
+		  }
+		}
+`
+	parser := NewTerraformPlanJsonParser(bytes.NewBufferString(inputTerraformPlanJson))
+	actualResources, err := parser.Parse()
+	require.NoError(t, err)
+	actualPlannedActions := make(map[string]PlannedAction)
+	for _, resource := range actualResources {
+		actualPlannedActions[resource.Address] = resource.PlannedAction
+	}
+	require.Equal(t, map[string]PlannedAction{
+		"aws_glue_catalog_database.kept":        PlannedActionNoOp,
+		"aws_glue_catalog_database.replaced[0]": PlannedActionReplace,
+		"aws_glue_catalog_database.old_name":    PlannedActionDelete,
+	}, actualPlannedActions)
+}
+
+func TestTerraformPlanJsonParserWithoutPriorState(t *testing.T) {
+	parser := NewTerraformPlanJsonParser(bytes.NewBufferString(`{"format_version": "1.2", "planned_values": {}}`))
+	actualResources, err := parser.Parse()
+	require.NoError(t, err)
+	require.Empty(t, actualResources)
+}
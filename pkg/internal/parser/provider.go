@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// TerraformProvider is the provider configuration which manages a resource
+type TerraformProvider struct {
+	// Name is the source address of the provider, e.g. registry.terraform.io/hashicorp/aws
+	Name string
+	// Alias is the alias of the provider configuration, it is empty for the
+	// default configuration
+	Alias string
+	// Module is the address of the module in which the provider configuration
+	// is declared, it is empty for the root module
+	Module string
+}
+
+// Type is the provider type which is also its default local name, e.g. aws
+func (provider TerraformProvider) Type() string {
+	return provider.Name[strings.LastIndex(provider.Name, "/")+1:]
+}
+
+// Reference is how the provider configuration is referenced within terraform
+// code, e.g. aws or aws.us_east_1
+func (provider TerraformProvider) Reference() string {
+	if len(provider.Alias) == 0 {
+		return provider.Type()
+	}
+	return fmt.Sprintf("%s.%s", provider.Type(), provider.Alias)
+}
+
+// Matches reports whether the provider is selected by a provider type (aws),
+// source address (registry.terraform.io/hashicorp/aws) or reference to an
+// aliased configuration (aws.us_east_1)
+func (provider TerraformProvider) Matches(name string) bool {
+	return name == provider.Name || name == provider.Type() || name == provider.Reference()
+}
+
+var stateFileProviderPattern = regexp.MustCompile(`^(?:(.+)\.)?provider(?:\["([^"]+)"]|\.([\w-]+))(?:\.([\w-]+))?$`)
+
+// parseStateFileProvider parses the provider configuration addresses stored in
+// state files, e.g. module.a.provider["registry.terraform.io/hashicorp/aws"].us_east_1
+// or provider.aws.us_east_1 as written by terraform 0.12
+func parseStateFileProvider(provider string) TerraformProvider {
+	match := stateFileProviderPattern.FindStringSubmatch(provider)
+	if match == nil {
+		return TerraformProvider{Name: provider}
+	}
+	name := match[2]
+	if len(name) == 0 {
+		name = match[3]
+	}
+	return TerraformProvider{
+		Name:   name,
+		Alias:  match[4],
+		Module: match[1],
+	}
+}
@@ -0,0 +1,48 @@
+package parser
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseStateFileProvider(t *testing.T) {
+	tests := []struct {
+		provider string
+		expected TerraformProvider
+	}{
+		{
+			provider: `provider["registry.terraform.io/hashicorp/aws"]`,
+			expected: TerraformProvider{Name: "registry.terraform.io/hashicorp/aws"},
+		},
+		{
+			provider: `provider["registry.terraform.io/hashicorp/aws"].us_east_1`,
+			expected: TerraformProvider{Name: "registry.terraform.io/hashicorp/aws", Alias: "us_east_1"},
+		},
+		{
+			provider: `module.app["a"].provider["registry.terraform.io/hashicorp/aws"].replica`,
+			expected: TerraformProvider{Name: "registry.terraform.io/hashicorp/aws", Alias: "replica", Module: `module.app["a"]`},
+		},
+		{
+			provider: "provider.aws.us_east_1",
+			expected: TerraformProvider{Name: "aws", Alias: "us_east_1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			require.Equal(t, tt.expected, parseStateFileProvider(tt.provider))
+		})
+	}
+}
+
+func TestTerraformProviderMatches(t *testing.T) {
+	defaultProvider := TerraformProvider{Name: "registry.terraform.io/hashicorp/aws"}
+	aliasedProvider := TerraformProvider{Name: "registry.terraform.io/hashicorp/aws", Alias: "us_east_1"}
+
+	require.True(t, defaultProvider.Matches("aws"))
+	require.True(t, defaultProvider.Matches("registry.terraform.io/hashicorp/aws"))
+	require.False(t, defaultProvider.Matches("aws.us_east_1"))
+	require.False(t, defaultProvider.Matches("google"))
+	require.True(t, aliasedProvider.Matches("aws"))
+	require.True(t, aliasedProvider.Matches("aws.us_east_1"))
+	require.Equal(t, "aws.us_east_1", aliasedProvider.Reference())
+}
@@ -0,0 +1,127 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+var (
+	_ TerraformStateParser = TerraformStateFileParser{}
+)
+
+// TerraformStateFileParser parses the raw state format (version 4) which is stored
+// in terraform.tfstate files and emitted by `terraform state pull`.
+type TerraformStateFileParser struct {
+	reader io.Reader
+}
+
+type stateFile struct {
+	Version   int                 `json:"version"`
+	Resources []stateFileResource `json:"resources"`
+}
+
+type stateFileResource struct {
+	Module    string                      `json:"module"`
+	Mode      string                      `json:"mode"`
+	Type      string                      `json:"type"`
+	Name      string                      `json:"name"`
+	Provider  string                      `json:"provider"`
+	Instances []stateFileResourceInstance `json:"instances"`
+}
+
+type stateFileResourceInstance struct {
+	IndexKey   any            `json:"index_key"`
+	Attributes map[string]any `json:"attributes"`
+	Identity   map[string]any `json:"identity"`
+	// Deposed is the key of an object which is left over from a create before
+	// destroy replacement. It is empty for the current object.
+	Deposed string `json:"deposed"`
+	// SensitiveAttributes are the paths to the sensitive values, each a list of
+	// steps like {"type": "get_attr", "value": "password"}. It is decoded
+	// separately, so that another layout does not fail the whole state.
+	SensitiveAttributes json.RawMessage `json:"sensitive_attributes"`
+}
+
+type stateFilePathStep struct {
+	Type  string `json:"type"`
+	Value any    `json:"value"`
+}
+
+func NewTerraformStateFileParser(reader io.Reader) TerraformStateParser {
+	return TerraformStateFileParser{
+		reader: reader,
+	}
+}
+
+func (parser TerraformStateFileParser) Parse() (TerraformResources, error) {
+	stateFileBytes, err := io.ReadAll(parser.reader)
+	if err != nil {
+		return nil, err
+	}
+	var state stateFile
+	err = json.Unmarshal(stateFileBytes, &state)
+	if err != nil {
+		return nil, err
+	}
+	if state.Version != 4 {
+		return nil, fmt.Errorf("unsupported state file version %d, only version 4 is supported", state.Version)
+	}
+	return parser.parseResources(state.Resources), nil
+}
+
+func (parser TerraformStateFileParser) parseResources(resources []stateFileResource) []TerraformResource {
+	var allResources []TerraformResource
+	for _, resource := range resources {
+		if resource.Mode != "managed" {
+			continue
+		}
+		for _, instance := range resource.Instances {
+			// a deposed object shares the address of the current object and is
+			// destroyed by terraform, so importing it would duplicate the target
+			if len(instance.Deposed) > 0 {
+				continue
+			}
+			allResources = append(allResources, TerraformResource{
+				Address:         parser.computeResourceAddress(resource, instance),
+				Type:            resource.Type,
+				Index:           instance.IndexKey,
+				AttributeValues: instance.Attributes,
+				SensitiveValues: parser.computeSensitiveValues(instance.SensitiveAttributes),
+				Provider:        parseStateFileProvider(resource.Provider),
+				Identity:        instance.Identity,
+			})
+		}
+	}
+	return allResources
+}
+
+// computeSensitiveValues marks the top level attributes containing sensitive
+// values as sensitive as a whole, which errs on the side of caution
+func (parser TerraformStateFileParser) computeSensitiveValues(sensitiveAttributes json.RawMessage) map[string]any {
+	var paths [][]stateFilePathStep
+	if err := json.Unmarshal(sensitiveAttributes, &paths); err != nil {
+		return nil
+	}
+	var sensitiveValues map[string]any
+	for _, path := range paths {
+		if len(path) == 0 || path[0].Type != "get_attr" {
+			continue
+		}
+		if name, ok := path[0].Value.(string); ok {
+			if sensitiveValues == nil {
+				sensitiveValues = make(map[string]any)
+			}
+			sensitiveValues[name] = true
+		}
+	}
+	return sensitiveValues
+}
+
+func (parser TerraformStateFileParser) computeResourceAddress(resource stateFileResource, instance stateFileResourceInstance) string {
+	resourceAddress := fmt.Sprintf("%s.%s%s", resource.Type, resource.Name, computeIndexSuffix(instance.IndexKey))
+	if len(resource.Module) == 0 {
+		return resourceAddress
+	}
+	return fmt.Sprintf("%s.%s", resource.Module, resourceAddress)
+}
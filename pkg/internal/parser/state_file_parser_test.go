@@ -0,0 +1,300 @@
+package parser
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestTerraformStateFileParserAddressComputation(t *testing.T) {
+	tests := []struct {
+		name                string
+		inputTerraformState string
+		computedAddresses   []string
+	}{
+		{
+			name: "root resource without index",
+			inputTerraformState: `
+				{
+				  "version": 4,
+				  "resources": [
+					{
+					  "mode": "managed",
+					  "type": "aws_glue_catalog_database",
+					  "name": "test_db",
+					  "instances": [
+						{
+						  "attributes": {
+							"id": "id_test_db"
+						  }
+						}
+					  ]
+					}
+				  ]
+				}
+`,
+			computedAddresses: []string{"aws_glue_catalog_database.test_db"},
+		},
+		{
+			name: "root resource with integer and string index",
+			inputTerraformState: `
+				{
+				  "version": 4,
+				  "resources": [
+					{
+					  "mode": "managed",
+					  "type": "aws_glue_catalog_database",
+					  "name": "counted",
+					  "each": "list",
+					  "instances": [
+						{
+						  "index_key": 0,
+						  "attributes": {
+							"id": "id_counted"
+						  }
+						}
+					  ]
+					},
+					{
+					  "mode": "managed",
+					  "type": "aws_glue_catalog_database",
+					  "name": "keyed",
+					  "each": "map",
+					  "instances": [
+						{
+						  "index_key": "one",
+						  "attributes": {
+							"id": "id_keyed"
+						  }
+						}
+					  ]
+					}
+				  ]
+				}
+`,
+			computedAddresses: []string{
+				"aws_glue_catalog_database.counted[0]",
+				`aws_glue_catalog_database.keyed["one"]`,
+			},
+		},
+		{
+			name: "nested resource within module instance",
+			inputTerraformState: `
+				{
+				  "version": 4,
+				  "resources": [
+					{
+					  "module": "module.test_mwaa[\"a\"].module.child",
+					  "mode": "managed",
+					  "type": "aws_iam_policy",
+					  "name": "test_mwaa_permissions",
+					  "instances": [
+						{
+						  "attributes": {
+							"id": "id_test_mwaa_permissions"
+						  }
+						}
+					  ]
+					}
+				  ]
+				}
+`,
+			computedAddresses: []string{`module.test_mwaa["a"].module.child.aws_iam_policy.test_mwaa_permissions`},
+		},
+		{
+			name: "data resources are skipped",
+			inputTerraformState: `
+				{
+				  "version": 4,
+				  "resources": [
+					{
+					  "mode": "data",
+					  "type": "aws_caller_identity",
+					  "name": "current",
+					  "instances": [
+						{
+						  "attributes": {
+							"id": "123456789012"
+						  }
+						}
+					  ]
+					}
+				  ]
+				}
+`,
+			computedAddresses: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewTerraformStateFileParser(bytes.NewBufferString(tt.inputTerraformState))
+			actualResources, err := parser.Parse()
+			require.NoError(t, err)
+			var actualAddresses []string
+			for _, resource := range actualResources {
+				actualAddresses = append(actualAddresses, resource.Address)
+			}
+			require.Equal(t, tt.computedAddresses, actualAddresses)
+		})
+	}
+}
+
+func TestTerraformStateFileParserRejectsUnsupportedVersion(t *testing.T) {
+	parser := NewTerraformStateFileParser(bytes.NewBufferString(`{"version": 3, "modules": []}`))
+	_, err := parser.Parse()
+	require.EqualError(t, err, "unsupported state file version 3, only version 4 is supported")
+}
+
+func TestNewTerraformStateParserDetectsInputFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected TerraformStateParser
+	}{
+		{
+			name:     "terraform show -json output",
+			input:    `{"format_version": "1.0", "values": {"root_module": {}}}`,
+			expected: TerraformStateJsonParser{},
+		},
+		{
+			name:     "terraform show -json plan output",
+			input:    `{"format_version": "1.2", "planned_values": {}, "resource_changes": []}`,
+			expected: TerraformPlanJsonParser{},
+		},
+		{
+			name:     "raw state file",
+			input:    `{"version": 4, "resources": []}`,
+			expected: TerraformStateFileParser{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := NewTerraformStateParser(bytes.NewBufferString(tt.input))
+			require.NoError(t, err)
+			require.IsType(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestNewTerraformStateParserRejectsUnknownInput(t *testing.T) {
+	_, err := NewTerraformStateParser(bytes.NewBufferString(`{"foo": "bar"}`))
+	require.Error(t, err)
+}
+
+func TestTerraformStateParsersReadResourceIdentity(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name: "terraform show -json output",
+			input: `{"format_version": "1.0", "values": {"root_module": {"resources": [
+				{"address": "aws_instance.web", "mode": "managed", "type": "aws_instance", "name": "web",
+				 "values": {"id": "i-1"}, "identity_schema_version": 0, "identity": {"id": "i-1", "region": "eu-west-1"}}
+			]}}}`,
+		},
+		{
+			name: "raw state file",
+			input: `{"version": 4, "resources": [
+				{"mode": "managed", "type": "aws_instance", "name": "web", "instances": [
+					{"attributes": {"id": "i-1"}, "identity_schema_version": 0, "identity": {"id": "i-1", "region": "eu-west-1"}}
+				]}
+			]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser, err := NewTerraformStateParser(bytes.NewBufferString(tt.input))
+			require.NoError(t, err)
+			resources, err := parser.Parse()
+			require.NoError(t, err)
+			require.Len(t, resources, 1)
+			require.Equal(t, map[string]any{"id": "i-1", "region": "eu-west-1"}, resources[0].Identity)
+		})
+	}
+}
+
+func TestTerraformStateParsersReadSensitiveValues(t *testing.T) {
+	tests := []struct {
+		name                    string
+		input                   string
+		expectedSensitiveValues map[string]any
+	}{
+		{
+			name: "terraform show -json output",
+			input: `{"format_version": "1.0", "values": {"root_module": {"resources": [
+				{"address": "aws_db_instance.main", "mode": "managed", "type": "aws_db_instance", "name": "main",
+				 "values": {"id": "db-1", "password": "secret", "tags": {}}, "sensitive_values": {"password": true, "tags": {}}}
+			]}}}`,
+			expectedSensitiveValues: map[string]any{"password": true, "tags": map[string]any{}},
+		},
+		{
+			name: "raw state file",
+			input: `{"version": 4, "resources": [
+				{"mode": "managed", "type": "aws_db_instance", "name": "main", "instances": [
+					{"attributes": {"id": "db-1", "password": "secret"}, "sensitive_attributes": [[{"type": "get_attr", "value": "password"}]]}
+				]}
+			]}`,
+			expectedSensitiveValues: map[string]any{"password": true},
+		},
+		{
+			name: "raw state file with an unknown layout of the sensitive attributes",
+			input: `{"version": 4, "resources": [
+				{"mode": "managed", "type": "aws_db_instance", "name": "main", "instances": [
+					{"attributes": {"id": "db-1"}, "sensitive_attributes": {"unknown": true}}
+				]}
+			]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser, err := NewTerraformStateParser(bytes.NewBufferString(tt.input))
+			require.NoError(t, err)
+			resources, err := parser.Parse()
+			require.NoError(t, err)
+			require.Len(t, resources, 1)
+			require.Equal(t, tt.expectedSensitiveValues, resources[0].SensitiveValues)
+		})
+	}
+}
+
+func TestTerraformStateParsersSkipDeposedObjects(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name: "terraform show -json output",
+			input: `{"format_version": "1.0", "values": {"root_module": {"resources": [
+				{"address": "aws_instance.web", "mode": "managed", "type": "aws_instance", "name": "web", "values": {"id": "i-new"}},
+				{"address": "aws_instance.web", "mode": "managed", "type": "aws_instance", "name": "web", "values": {"id": "i-old"}, "deposed_key": "00000001"}
+			]}}}`,
+		},
+		{
+			name: "prior state of a plan",
+			input: `{"format_version": "1.2", "planned_values": {"root_module": {}}, "prior_state": {"format_version": "1.0", "values": {"root_module": {"resources": [
+				{"address": "aws_instance.web", "mode": "managed", "type": "aws_instance", "name": "web", "values": {"id": "i-new"}},
+				{"address": "aws_instance.web", "mode": "managed", "type": "aws_instance", "name": "web", "values": {"id": "i-old"}, "deposed_key": "00000001"}
+			]}}}}`,
+		},
+		{
+			name: "raw state file",
+			input: `{"version": 4, "resources": [
+				{"mode": "managed", "type": "aws_instance", "name": "web", "instances": [
+					{"attributes": {"id": "i-new"}},
+					{"attributes": {"id": "i-old"}, "deposed": "00000001"}
+				]}
+			]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser, err := NewTerraformStateParser(bytes.NewBufferString(tt.input))
+			require.NoError(t, err)
+			resources, err := parser.Parse()
+			require.NoError(t, err)
+			require.Len(t, resources, 1)
+			require.Equal(t, "i-new", resources[0].AttributeValues["id"])
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package tfimportgen
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// AddressMapping rewrites the resource addresses from the source state which
+// start with From (matched on whole address segments) to start with To instead
+type AddressMapping struct {
+	From string
+	To   string
+}
+
+// ParseAddressMapping parses a mapping given in the form "from=to". The
+// addresses may contain = within quoted instance keys.
+func ParseAddressMapping(mapping string) (AddressMapping, error) {
+	from, to, found := cutOutsideQuotes(mapping, '=')
+	from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+	if !found || len(from) == 0 || len(to) == 0 {
+		return AddressMapping{}, fmt.Errorf("invalid address mapping %q, expected the form from=to", mapping)
+	}
+	return AddressMapping{From: from, To: to}, nil
+}
+
+// cutOutsideQuotes slices the string around the first separator which is not
+// within a quoted string, honouring backslash escapes within the quotes
+func cutOutsideQuotes(s string, separator byte) (string, string, bool) {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quoted && s[i] == '\\':
+			i++
+		case s[i] == '"':
+			quoted = !quoted
+		case !quoted && s[i] == separator:
+			return s[:i], s[i+1:], true
+		}
+	}
+	return s, "", false
+}
+
+// LoadAddressMappings reads a mapping file with one mapping in the form
+// "from=to" per line, like the one written by SuggestAddressMappings. Empty
+// lines and lines starting with # are ignored.
+func LoadAddressMappings(reader io.Reader) ([]AddressMapping, error) {
+	var mappings []AddressMapping
+	scanner := bufio.NewScanner(reader)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		mapping, err := ParseAddressMapping(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		mappings = append(mappings, mapping)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("invalid address mappings: %w", err)
+	}
+	return mappings, nil
+}
+
+func (mapping AddressMapping) matches(address string) bool {
+	return address == mapping.From ||
+		strings.HasPrefix(address, mapping.From+".") ||
+		strings.HasPrefix(address, mapping.From+"[")
+}
+
+type AddressMappings []AddressMapping
+
+// apply rewrites the address using the most specific matching mapping
+func (mappings AddressMappings) apply(address string) string {
+	var mostSpecificMapping *AddressMapping
+	for i, mapping := range mappings {
+		if mapping.matches(address) && (mostSpecificMapping == nil || len(mapping.From) > len(mostSpecificMapping.From)) {
+			mostSpecificMapping = &mappings[i]
+		}
+	}
+	if mostSpecificMapping == nil {
+		return address
+	}
+	return mostSpecificMapping.To + strings.TrimPrefix(address, mostSpecificMapping.From)
+}
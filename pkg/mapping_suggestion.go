@@ -0,0 +1,218 @@
+package tfimportgen
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/kishaningithub/tf-import-gen/pkg/internal/parser"
+)
+
+// AddressMappingSuggestion is an address mapping suggested by matching a
+// resource from the source state to a resource block of the target configuration
+type AddressMappingSuggestion struct {
+	// From is the address of the resource in the source state without its
+	// instance key, so that the mapping applies to all of its instances
+	From string
+	// To is the address of the matching resource block. It is empty when no
+	// resource block of the type is left to match.
+	To string
+	// Similarity of the resource names and the module paths, between 0 and 1
+	Similarity float64
+	// Ambiguous is set when other suggestions have the same To, because the
+	// module instance keys of From were dropped for a module block without
+	// count or for_each. The suggestion is rendered commented out.
+	Ambiguous bool
+}
+
+type AddressMappingSuggestions []AddressMappingSuggestion
+
+// AddressMappings returns the suggested mappings, leaving out the resources
+// which no resource block was found for and the ambiguous suggestions
+func (suggestions AddressMappingSuggestions) AddressMappings() []AddressMapping {
+	var mappings []AddressMapping
+	for _, suggestion := range suggestions {
+		if len(suggestion.To) > 0 && !suggestion.Ambiguous {
+			mappings = append(mappings, AddressMapping{From: suggestion.From, To: suggestion.To})
+		}
+	}
+	return mappings
+}
+
+// String renders the suggestions as a mapping file, which is meant to be
+// reviewed and edited before it is given to LoadAddressMappings
+func (suggestions AddressMappingSuggestions) String() string {
+	var suggestionsStr strings.Builder
+	suggestionsStr.WriteString("# address mappings in the form from=to, review them before applying them\n")
+	for _, suggestion := range suggestions {
+		suggestionsStr.WriteString("\n")
+		if len(suggestion.To) == 0 {
+			fromAddress, _ := parser.ParseAddress(suggestion.From)
+			suggestionsStr.WriteString(fmt.Sprintf("# no resource block of type %s is left for %s\n", fromAddress.Type, suggestion.From))
+			continue
+		}
+		suggestionsStr.WriteString(fmt.Sprintf("# similarity %.2f\n", suggestion.Similarity))
+		if suggestion.Ambiguous {
+			suggestionsStr.WriteString(fmt.Sprintf("# warning: %s has neither count nor for_each, so other resources are mapped to it as well\n", suggestion.To))
+			suggestionsStr.WriteString(fmt.Sprintf("# %s=%s\n", suggestion.From, suggestion.To))
+			continue
+		}
+		suggestionsStr.WriteString(fmt.Sprintf("%s=%s\n", suggestion.From, suggestion.To))
+	}
+	return suggestionsStr.String()
+}
+
+// sourceResourceBlock are the instances of a resource in the source state
+type sourceResourceBlock struct {
+	address parser.Address
+	// addresses are the distinct addresses of the instances without the
+	// instance key of the resource, which differ in their module instance keys
+	addresses []parser.Address
+}
+
+// SuggestAddressMappings matches the resources the imports were generated for to
+// the resource blocks of the target configuration which are not imported into
+// yet. Resources are only matched to resource blocks of the same type, the one
+// with the most similar name and module path winning. Resources which already
+// have a resource block, and resources within modules whose source is not a
+// local directory, get no suggestion.
+func (configuration TargetConfiguration) SuggestAddressMappings(imports TerraformImports) AddressMappingSuggestions {
+	resourceBlocks := configuration.resourceBlocks()
+	matchedResourceBlocks := make(map[string]bool)
+	for _, resourceBlock := range resourceBlocks {
+		matchedResourceBlocks[resourceBlock.address.String()] = false
+	}
+
+	var sourceResourceBlocks []*sourceResourceBlock
+	sourceResourceBlockIndexes := make(map[string]int)
+	for _, terraformImport := range imports {
+		sourceAddress := cmp.Or(terraformImport.SourceAddress, terraformImport.ResourceAddress)
+		address, err := parser.ParseAddress(sourceAddress)
+		if err != nil || address.IsModule() || configuration.hasUnverifiedModule(address.Module) {
+			continue
+		}
+		address.Key = nil
+		block := address.WithoutKeys()
+		if _, ok := matchedResourceBlocks[block.String()]; ok {
+			// the resource already has a resource block
+			matchedResourceBlocks[block.String()] = true
+			continue
+		}
+		index, ok := sourceResourceBlockIndexes[block.String()]
+		if !ok {
+			index = len(sourceResourceBlocks)
+			sourceResourceBlockIndexes[block.String()] = index
+			sourceResourceBlocks = append(sourceResourceBlocks, &sourceResourceBlock{address: block})
+		}
+		sourceBlock := sourceResourceBlocks[index]
+		if !slices.ContainsFunc(sourceBlock.addresses, func(other parser.Address) bool { return other.String() == address.String() }) {
+			sourceBlock.addresses = append(sourceBlock.addresses, address)
+		}
+	}
+
+	type candidate struct {
+		source        int
+		resourceBlock targetResourceBlock
+		similarity    float64
+	}
+	var candidates []candidate
+	for i, sourceBlock := range sourceResourceBlocks {
+		for _, resourceBlock := range resourceBlocks {
+			if resourceBlock.address.Type == sourceBlock.address.Type && !matchedResourceBlocks[resourceBlock.address.String()] {
+				candidates = append(candidates, candidate{source: i, resourceBlock: resourceBlock, similarity: addressSimilarity(sourceBlock.address, resourceBlock.address)})
+			}
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		return cmp.Compare(b.similarity, a.similarity)
+	})
+	matches := make(map[int]candidate)
+	for _, candidate := range candidates {
+		if _, ok := matches[candidate.source]; ok || matchedResourceBlocks[candidate.resourceBlock.address.String()] {
+			continue
+		}
+		matches[candidate.source] = candidate
+		matchedResourceBlocks[candidate.resourceBlock.address.String()] = true
+	}
+
+	var suggestions AddressMappingSuggestions
+	suggestionCounts := make(map[string]int)
+	for i, sourceBlock := range sourceResourceBlocks {
+		match, matched := matches[i]
+		for _, address := range sourceBlock.addresses {
+			suggestion := AddressMappingSuggestion{From: address.String()}
+			if matched {
+				suggestion.To = withModuleKeys(match.resourceBlock, address.Module).String()
+				suggestion.Similarity = match.similarity
+				suggestionCounts[suggestion.To]++
+			}
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+	// the module instance keys are dropped for module blocks without count or
+	// for_each, which maps the instances of different modules to the same address
+	for i := range suggestions {
+		suggestions[i].Ambiguous = suggestionCounts[suggestions[i].To] > 1
+	}
+	return suggestions
+}
+
+// withModuleKeys returns the address of the resource block within the module
+// instances of the source resource, carrying over the module instance keys
+// which match the count or for_each of the module blocks
+func withModuleKeys(resourceBlock targetResourceBlock, sourceModule []parser.ModuleInstance) parser.Address {
+	address := resourceBlock.address
+	address.Module = slices.Clone(address.Module)
+	for i := range address.Module {
+		if i < len(sourceModule) && resourceBlock.moduleKeyStyles[i] != keyStyleNone && resourceBlock.moduleKeyStyles[i] == keyStyleOf(sourceModule[i].Key) {
+			address.Module[i].Key = sourceModule[i].Key
+		}
+	}
+	return address
+}
+
+// addressSimilarity weighs the similarity of the resource names twice as much
+// as the similarity of the module paths
+func addressSimilarity(a parser.Address, b parser.Address) float64 {
+	nameSimilarity := similarity(a.Name, b.Name)
+	moduleSimilarity := similarity(moduleNames(a), moduleNames(b))
+	return (2*nameSimilarity + moduleSimilarity) / 3
+}
+
+func moduleNames(address parser.Address) string {
+	var names []string
+	for _, moduleInstance := range address.Module {
+		names = append(names, moduleInstance.Name)
+	}
+	return strings.Join(names, ".")
+}
+
+// similarity is one minus the levenshtein distance of the strings relative to
+// the length of the longer one
+func similarity(a string, b string) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	return 1 - float64(levenshteinDistance(a, b))/float64(max(len(a), len(b)))
+}
+
+func levenshteinDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			substitutionCost := 1
+			if a[i-1] == b[j-1] {
+				substitutionCost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+substitutionCost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
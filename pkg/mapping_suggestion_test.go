@@ -0,0 +1,91 @@
+package tfimportgen_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	tfimportgen "github.com/kishaningithub/tf-import-gen/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTargetConfiguration_SuggestAddressMappings(t *testing.T) {
+	configuration, err := tfimportgen.LoadTargetConfiguration(filepath.FromSlash("testdata/target"))
+	require.NoError(t, err)
+	var imports tfimportgen.TerraformImports
+	for _, address := range []string{
+		"aws_s3_bucket.logs",
+		"aws_s3_bucket.log_archive",
+		"aws_instance.webserver[0]",
+		"aws_instance.webserver[1]",
+		"module.net.aws_vpc.main",
+		`module.region["eu-west-1"].aws_subnet.private[0]`,
+		`module.region["us-east-1"].aws_subnet.private[0]`,
+		"aws_iam_role.deployer",
+		"module.vpc.aws_vpc.this[0]",
+	} {
+		imports = append(imports, tfimportgen.TerraformImport{ResourceAddress: address, ResourceID: "id", SupportsImport: true})
+	}
+
+	suggestions := configuration.SuggestAddressMappings(imports)
+
+	expectedMappings := []tfimportgen.AddressMapping{
+		{From: "aws_s3_bucket.log_archive", To: "aws_s3_bucket.this"},
+		{From: "aws_instance.webserver", To: "aws_instance.web"},
+		{From: "module.net.aws_vpc.main", To: "module.network.aws_vpc.main"},
+		{From: `module.region["eu-west-1"].aws_subnet.private`, To: `module.regions["eu-west-1"].aws_subnet.private`},
+		{From: `module.region["us-east-1"].aws_subnet.private`, To: `module.regions["us-east-1"].aws_subnet.private`},
+	}
+	require.Equal(t, expectedMappings, suggestions.AddressMappings())
+	require.Equal(t, tfimportgen.AddressMappingSuggestion{From: "aws_iam_role.deployer"}, suggestions[len(suggestions)-1])
+
+	mappings, err := tfimportgen.LoadAddressMappings(bytes.NewBufferString(suggestions.String()))
+	require.NoError(t, err)
+	require.Equal(t, expectedMappings, mappings)
+
+	mappedImports, err := tfimportgen.GenerateImports(bytes.NewBufferString(`{"format_version": "1.0", "values": {"root_module": {"resources": [
+		{"address": "aws_instance.webserver[1]", "mode": "managed", "type": "aws_instance", "name": "webserver", "index": 1, "values": {"id": "i-1"}}
+	]}}}`), nil, tfimportgen.WithAddressMappings(mappings...))
+	require.NoError(t, err)
+	require.Equal(t, "aws_instance.web[1]", mappedImports[0].ResourceAddress)
+}
+
+func TestAddressMappingSuggestions_String(t *testing.T) {
+	suggestions := tfimportgen.AddressMappingSuggestions{
+		{From: "aws_s3_bucket.log_archive", To: "aws_s3_bucket.this", Similarity: 0.25},
+		{From: "aws_iam_role.deployer"},
+	}
+
+	expected := `# address mappings in the form from=to, review them before applying them
+
+# similarity 0.25
+aws_s3_bucket.log_archive=aws_s3_bucket.this
+
+# no resource block of type aws_iam_role is left for aws_iam_role.deployer
+`
+	require.Equal(t, expected, suggestions.String())
+}
+
+func TestTargetConfiguration_SuggestAddressMappings_ShouldNotMapModuleInstancesToTheSameAddress(t *testing.T) {
+	configuration, err := tfimportgen.LoadTargetConfiguration(filepath.FromSlash("testdata/target"))
+	require.NoError(t, err)
+	imports := tfimportgen.TerraformImports{
+		{ResourceAddress: "module.net[0].aws_vpc.main", ResourceID: "vpc-1", SupportsImport: true},
+		{ResourceAddress: "module.net[1].aws_vpc.main", ResourceID: "vpc-2", SupportsImport: true},
+	}
+
+	suggestions := configuration.SuggestAddressMappings(imports)
+
+	require.Empty(t, suggestions.AddressMappings())
+	expected := `# address mappings in the form from=to, review them before applying them
+
+# similarity 0.81
+# warning: module.network.aws_vpc.main has neither count nor for_each, so other resources are mapped to it as well
+# module.net[0].aws_vpc.main=module.network.aws_vpc.main
+
+# similarity 0.81
+# warning: module.network.aws_vpc.main has neither count nor for_each, so other resources are mapped to it as well
+# module.net[1].aws_vpc.main=module.network.aws_vpc.main
+`
+	require.Equal(t, expected, suggestions.String())
+}
@@ -0,0 +1,58 @@
+package tfimportgen
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseAddressMapping(t *testing.T) {
+	mapping, err := ParseAddressMapping(" module.old = module.new ")
+	require.NoError(t, err)
+	require.Equal(t, AddressMapping{From: "module.old", To: "module.new"}, mapping)
+
+	mapping, err = ParseAddressMapping(`aws_ssm_parameter.this["a=\"b"]=aws_ssm_parameter.params["a=b"]`)
+	require.NoError(t, err)
+	require.Equal(t, AddressMapping{From: `aws_ssm_parameter.this["a=\"b"]`, To: `aws_ssm_parameter.params["a=b"]`}, mapping)
+
+	for _, invalidMapping := range []string{"module.old", "module.old=", "=module.new", `module.old["a=b"]`} {
+		_, err = ParseAddressMapping(invalidMapping)
+		require.EqualError(t, err, fmt.Sprintf("invalid address mapping %q, expected the form from=to", invalidMapping))
+	}
+}
+
+func Test_AddressMappings_Apply(t *testing.T) {
+	mappings := AddressMappings{
+		{From: "module.old", To: "module.new"},
+		{From: "module.old.aws_s3_bucket.logs", To: "aws_s3_bucket.logs"},
+		{From: "aws_s3_bucket.a", To: "aws_s3_bucket.b"},
+	}
+	tests := []struct {
+		address  string
+		expected string
+	}{
+		{address: "module.old.aws_iam_role.this", expected: "module.new.aws_iam_role.this"},
+		{address: `module.old["x"].aws_iam_role.this`, expected: `module.new["x"].aws_iam_role.this`},
+		{address: "module.old.aws_s3_bucket.logs[0]", expected: "aws_s3_bucket.logs[0]"},
+		{address: "module.older.aws_iam_role.this", expected: "module.older.aws_iam_role.this"},
+		{address: "aws_s3_bucket.a", expected: "aws_s3_bucket.b"},
+		{address: `aws_s3_bucket.a["k"]`, expected: `aws_s3_bucket.b["k"]`},
+		{address: "aws_s3_bucket.ab", expected: "aws_s3_bucket.ab"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			require.Equal(t, tt.expected, mappings.apply(tt.address))
+		})
+	}
+}
+
+func Test_LoadAddressMappings(t *testing.T) {
+	mappings, err := LoadAddressMappings(strings.NewReader("# renamed modules\n\nmodule.old=module.new\n  aws_s3_bucket.a = aws_s3_bucket.b\n"))
+	require.NoError(t, err)
+	require.Equal(t, []AddressMapping{{From: "module.old", To: "module.new"}, {From: "aws_s3_bucket.a", To: "aws_s3_bucket.b"}}, mappings)
+
+	_, err = LoadAddressMappings(strings.NewReader("module.old=module.new\nmodule.other\n"))
+	require.EqualError(t, err, `line 2: invalid address mapping "module.other", expected the form from=to`)
+}
@@ -0,0 +1,118 @@
+package tfimportgen
+
+import (
+	"github.com/kishaningithub/tf-import-gen/pkg/internal/parser"
+)
+
+// Option customizes how GenerateImports selects and converts resources
+type Option func(*options)
+
+type options struct {
+	plannedActions    []parser.PlannedAction
+	addressMappings   AddressMappings
+	keyMappings       KeyMappings
+	idRules           []IDRule
+	regexAddresses    bool
+	excludedAddresses []string
+	excludedTypes     []string
+	providers         []string
+	attributeValues   bool
+	providerSchemas   ProviderSchemas
+}
+
+// WithPlannedActions selects only those resources on which the plan intends to
+// take one of the given actions (no-op, update, delete, replace, forget).
+// This option requires the input to be the json representation of a plan.
+func WithPlannedActions(plannedActions ...string) Option {
+	return func(options *options) {
+		for _, plannedAction := range plannedActions {
+			options.plannedActions = append(options.plannedActions, parser.PlannedAction(plannedAction))
+		}
+	}
+}
+
+// WithRegexAddresses interprets the addresses given to GenerateImports as
+// regular expressions which are evaluated per address segment, e.g.
+// module.team_.*.aws_iam_.* instead of glob patterns like module.team_*.aws_iam_*.*
+func WithRegexAddresses() Option {
+	return func(options *options) {
+		options.regexAddresses = true
+	}
+}
+
+// WithExcludedAddresses drops the resources contained in any of the given
+// addresses, after the addresses given to GenerateImports are applied. Like
+// those, they are regular expressions when used WithRegexAddresses.
+func WithExcludedAddresses(addresses ...string) Option {
+	return func(options *options) {
+		options.excludedAddresses = append(options.excludedAddresses, addresses...)
+	}
+}
+
+// WithExcludedTypes drops the resources of the given types, which may be glob
+// patterns like aws_iam_*
+func WithExcludedTypes(types ...string) Option {
+	return func(options *options) {
+		options.excludedTypes = append(options.excludedTypes, types...)
+	}
+}
+
+// WithProviders selects only the resources managed by one of the given
+// providers, given as type (aws), source address (registry.terraform.io/hashicorp/aws)
+// or reference to an aliased configuration (aws.us_east_1)
+func WithProviders(providers ...string) Option {
+	return func(options *options) {
+		options.providers = append(options.providers, providers...)
+	}
+}
+
+// WithAddressMappings rewrites the address every import is generated for.
+// When multiple mappings match an address, the most specific one is used.
+func WithAddressMappings(addressMappings ...AddressMapping) Option {
+	return func(options *options) {
+		options.addressMappings = append(options.addressMappings, addressMappings...)
+	}
+}
+
+// WithKeyMappings converts count resources to for_each resources by replacing
+// the numeric instance keys with the value of an attribute. The key mappings
+// are applied before the address mappings. Instances which are re-keyed to the
+// same address make GenerateImports fail.
+func WithKeyMappings(keyMappings ...KeyMapping) Option {
+	return func(options *options) {
+		options.keyMappings = append(options.keyMappings, keyMappings...)
+	}
+}
+
+// WithIDRules adds rules for computing the import identifier of resource types.
+// They take precedence over the builtin rules of the same resource type.
+func WithIDRules(idRules ...IDRule) Option {
+	return func(options *options) {
+		options.idRules = append(options.idRules, idRules...)
+	}
+}
+
+// WithAttributeValues populates TerraformImport.AttributeValues, which are
+// needed for rendering resource configuration with FormatConfig
+func WithAttributeValues() Option {
+	return func(options *options) {
+		options.attributeValues = true
+	}
+}
+
+// WithProviderSchemas prunes the resource configuration rendered with
+// FormatConfig to the attributes and nested blocks which can be configured
+// according to the schemas of the providers
+func WithProviderSchemas(providerSchemas ProviderSchemas) Option {
+	return func(options *options) {
+		options.providerSchemas = providerSchemas
+	}
+}
+
+func newOptions(opts []Option) options {
+	var options options
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
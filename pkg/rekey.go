@@ -0,0 +1,119 @@
+package tfimportgen
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kishaningithub/tf-import-gen/pkg/internal/parser"
+)
+
+// KeyMapping converts count resources to for_each resources by replacing the
+// numeric instance keys of the resources contained in Resource with the value
+// of their Attribute, e.g. aws_s3_bucket.this[0] to aws_s3_bucket.this["logs"]
+type KeyMapping struct {
+	// Resource is an address like the ones given to GenerateImports, e.g.
+	// aws_s3_bucket.this or module.*.aws_s3_bucket.*
+	Resource  string
+	Attribute string
+}
+
+// ParseKeyMapping parses a mapping given in the form "resource=attribute"
+func ParseKeyMapping(mapping string) (KeyMapping, error) {
+	resource, attribute, found := strings.Cut(mapping, "=")
+	resource, attribute = strings.TrimSpace(resource), strings.TrimSpace(attribute)
+	if !found || len(resource) == 0 || len(attribute) == 0 {
+		return KeyMapping{}, fmt.Errorf("invalid key mapping %q, expected the form resource=attribute", mapping)
+	}
+	return KeyMapping{Resource: resource, Attribute: attribute}, nil
+}
+
+type KeyMappings []KeyMapping
+
+type compiledKeyMapping struct {
+	resource  parser.Address
+	attribute string
+}
+
+type compiledKeyMappings []compiledKeyMapping
+
+func (mappings KeyMappings) compile() (compiledKeyMappings, error) {
+	var compiledMappings compiledKeyMappings
+	for _, mapping := range mappings {
+		resource, err := parser.ParseAddress(mapping.Resource)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key mapping %s=%s: %w", mapping.Resource, mapping.Attribute, err)
+		}
+		if resource.IsModule() || len(mapping.Attribute) == 0 {
+			return nil, fmt.Errorf("invalid key mapping %s=%s, expected a resource and an attribute", mapping.Resource, mapping.Attribute)
+		}
+		compiledMappings = append(compiledMappings, compiledKeyMapping{resource: resource, attribute: mapping.Attribute})
+	}
+	return compiledMappings, nil
+}
+
+// apply returns the address of the resource with its numeric instance key
+// replaced using the first matching mapping. The address is returned as it is
+// when no mapping matches.
+func (mappings compiledKeyMappings) apply(resource parser.TerraformResource) (string, bool, error) {
+	address, err := parser.ParseAddress(resource.Address)
+	if err != nil {
+		return resource.Address, false, nil
+	}
+	if _, ok := address.Key.(int); !ok {
+		return resource.Address, false, nil
+	}
+	for _, mapping := range mappings {
+		if !mapping.resource.Contains(address) {
+			continue
+		}
+		switch value := resource.AttributeValues[mapping.attribute].(type) {
+		case nil:
+			return "", false, fmt.Errorf("cannot re-key %s, it does not have the attribute %s", resource.Address, mapping.attribute)
+		case string:
+			if len(value) == 0 {
+				return "", false, fmt.Errorf("cannot re-key %s, its attribute %s is empty", resource.Address, mapping.attribute)
+			}
+			address.Key = value
+		default:
+			// for_each keys are strings, and numbers or bools have no single
+			// string form terraform would agree with
+			return "", false, fmt.Errorf("cannot re-key %s, its attribute %s is not a string", resource.Address, mapping.attribute)
+		}
+		return address.String(), true, nil
+	}
+	return resource.Address, false, nil
+}
+
+// keyCollisions tracks the destination addresses of all inputs to report the
+// instances which were re-keyed to the address of another instance
+type keyCollisions struct {
+	destinations map[string]keyCollisionSource
+	errs         []error
+}
+
+type keyCollisionSource struct {
+	address string
+	rekeyed bool
+}
+
+func (collisions *keyCollisions) add(sourceAddress string, destinationAddress string, rekeyed bool) {
+	if collisions.destinations == nil {
+		collisions.destinations = make(map[string]keyCollisionSource)
+	}
+	other, ok := collisions.destinations[destinationAddress]
+	switch {
+	case !ok:
+		collisions.destinations[destinationAddress] = keyCollisionSource{address: sourceAddress, rekeyed: rekeyed}
+	case other.rekeyed && rekeyed:
+		collisions.errs = append(collisions.errs, fmt.Errorf("key collision, both %s and %s are re-keyed to %s", other.address, sourceAddress, destinationAddress))
+	case other.rekeyed:
+		collisions.errs = append(collisions.errs, fmt.Errorf("key collision, %s is re-keyed to %s, which %s is imported to as well", other.address, destinationAddress, sourceAddress))
+	case rekeyed:
+		collisions.errs = append(collisions.errs, fmt.Errorf("key collision, %s is re-keyed to %s, which %s is imported to as well", sourceAddress, destinationAddress, other.address))
+	}
+}
+
+func (collisions *keyCollisions) err() error {
+	return errors.Join(collisions.errs...)
+}
@@ -0,0 +1,134 @@
+package tfimportgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+	"text/template"
+)
+
+// IDRule describes how the import identifier of a resource type is computed.
+//
+// ID is a text/template which is evaluated against the attribute values of
+// the resource, e.g. "{{.rest_api_id}}/{{.id}}". Besides the builtin template
+// functions, join, split, last and replace are available. When ID is empty
+// the id attribute of the resource is used.
+type IDRule struct {
+	Type        string `json:"type"`
+	ID          string `json:"id,omitempty"`
+	Unsupported bool   `json:"unsupported,omitempty"`
+}
+
+// LoadIDRules reads a json array of rules, e.g.
+//
+//	[
+//	  {"type": "mycorp_dns_record", "id": "{{.zone}}/{{.name}}"},
+//	  {"type": "mycorp_legacy_thing", "unsupported": true}
+//	]
+func LoadIDRules(reader io.Reader) ([]IDRule, error) {
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	var rules []IDRule
+	err := decoder.Decode(&rules)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id rules: %w", err)
+	}
+	return rules, nil
+}
+
+const defaultIDTemplate = "{{.id}}"
+
+var idTemplateFuncs = template.FuncMap{
+	"join": func(values []any, separator string) string {
+		return strings.Join(convertToStrings(values), separator)
+	},
+	"split": strings.Split,
+	"last": func(values []string) string {
+		if len(values) == 0 {
+			return ""
+		}
+		return values[len(values)-1]
+	},
+	"replace":            strings.ReplaceAll,
+	requiredTemplateFunc: required,
+}
+
+type compiledIDRule struct {
+	idTemplate         *template.Template
+	requiredAttributes []requiredAttribute
+	unsupported        bool
+}
+
+// defaultIDRule is used for the resource types without a rule
+var defaultIDRule = mustCompileIDRule(IDRule{Type: "default"})
+
+func mustCompileIDRule(rule IDRule) compiledIDRule {
+	compiledRule, err := compileIDRule(rule)
+	if err != nil {
+		panic(err)
+	}
+	return compiledRule
+}
+
+func compileIDRule(rule IDRule) (compiledIDRule, error) {
+	idTemplateText := rule.ID
+	if len(idTemplateText) == 0 {
+		idTemplateText = defaultIDTemplate
+	}
+	idTemplate, err := template.New(rule.Type).Funcs(idTemplateFuncs).Parse(idTemplateText)
+	if err != nil {
+		return compiledIDRule{}, fmt.Errorf("invalid id rule for %s: %w", rule.Type, err)
+	}
+	requireBranchAttributes(idTemplate.Tree)
+	return compiledIDRule{
+		idTemplate:         idTemplate,
+		requiredAttributes: findRequiredAttributes(idTemplate.Tree),
+		unsupported:        rule.Unsupported,
+	}, nil
+}
+
+type idRuleRegistry struct {
+	rules map[string]compiledIDRule
+}
+
+var defaultIDRuleRegistry = mustNewIDRuleRegistry(builtinIDRules)
+
+func mustNewIDRuleRegistry(rules []IDRule) idRuleRegistry {
+	registry, err := idRuleRegistry{}.with(rules)
+	if err != nil {
+		panic(err)
+	}
+	return registry
+}
+
+// with returns a copy of the registry in which the given rules take precedence
+// over the already registered rules of the same type
+func (registry idRuleRegistry) with(rules []IDRule) (idRuleRegistry, error) {
+	compiledRules := maps.Clone(registry.rules)
+	if compiledRules == nil {
+		compiledRules = make(map[string]compiledIDRule)
+	}
+	for _, rule := range rules {
+		if len(rule.Type) == 0 {
+			return idRuleRegistry{}, fmt.Errorf("id rule with id %q does not have a type", rule.ID)
+		}
+		compiledRule, err := compileIDRule(rule)
+		if err != nil {
+			return idRuleRegistry{}, err
+		}
+		compiledRules[rule.Type] = compiledRule
+	}
+	return idRuleRegistry{rules: compiledRules}, nil
+}
+
+// lookup returns the rule of the resource type, which is the default rule for
+// the resource types without a rule
+func (registry idRuleRegistry) lookup(resourceType string) (compiledIDRule, bool) {
+	rule, ok := registry.rules[resourceType]
+	if !ok {
+		return defaultIDRule, false
+	}
+	return rule, true
+}
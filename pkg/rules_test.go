@@ -0,0 +1,54 @@
+package tfimportgen_test
+
+import (
+	"bytes"
+	"testing"
+
+	tfimportgen "github.com/kishaningithub/tf-import-gen/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LoadIDRules(t *testing.T) {
+	rules, err := tfimportgen.LoadIDRules(bytes.NewBufferString(`[
+		{"type": "mycorp_dns_record", "id": "{{.zone}}/{{.name}}"},
+		{"type": "mycorp_legacy_thing", "unsupported": true}
+	]`))
+
+	require.NoError(t, err)
+	require.Equal(t, []tfimportgen.IDRule{
+		{Type: "mycorp_dns_record", ID: "{{.zone}}/{{.name}}"},
+		{Type: "mycorp_legacy_thing", Unsupported: true},
+	}, rules)
+}
+
+func Test_LoadIDRules_ShouldRejectUnknownFields(t *testing.T) {
+	_, err := tfimportgen.LoadIDRules(bytes.NewBufferString(`[{"type": "mycorp_dns_record", "template": "{{.zone}}"}]`))
+
+	require.EqualError(t, err, `invalid id rules: json: unknown field "template"`)
+}
+
+func Test_GenerateImports_ShouldRejectInvalidIDRules(t *testing.T) {
+	tests := []struct {
+		name          string
+		rule          tfimportgen.IDRule
+		expectedError string
+	}{
+		{
+			name:          "missing type",
+			rule:          tfimportgen.IDRule{ID: "{{.name}}"},
+			expectedError: `id rule with id "{{.name}}" does not have a type`,
+		},
+		{
+			name:          "invalid template",
+			rule:          tfimportgen.IDRule{Type: "mycorp_dns_record", ID: "{{.name"},
+			expectedError: `invalid id rule for mycorp_dns_record: template: mycorp_dns_record:1: unclosed action`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tfimportgen.GenerateImports(bytes.NewBufferString("{}"), nil, tfimportgen.WithIDRules(tt.rule))
+
+			require.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
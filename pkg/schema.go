@@ -0,0 +1,151 @@
+package tfimportgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/kishaningithub/tf-import-gen/pkg/internal/parser"
+)
+
+// ProviderSchemas are the schemas of the providers as printed by
+// `terraform providers schema -json`. They tell which attributes of a resource
+// can be configured, which are nested blocks and which are sensitive.
+type ProviderSchemas struct {
+	schemas *tfjson.ProviderSchemas
+}
+
+// LoadProviderSchemas reads the output of `terraform providers schema -json`
+func LoadProviderSchemas(reader io.Reader) (ProviderSchemas, error) {
+	var schemas tfjson.ProviderSchemas
+	err := json.NewDecoder(reader).Decode(&schemas)
+	if err != nil {
+		return ProviderSchemas{}, fmt.Errorf("invalid provider schemas: %w", err)
+	}
+	return ProviderSchemas{schemas: &schemas}, nil
+}
+
+// lookup returns the schema of the resource type of the given provider. Older
+// versions of `terraform show -json` do not record the source address of the
+// provider, in which case any provider with the type is used.
+func (providerSchemas ProviderSchemas) lookup(providerName string, resourceType string) *tfjson.SchemaBlock {
+	if providerSchemas.schemas == nil {
+		return nil
+	}
+	if providerSchema, ok := providerSchemas.schemas.Schemas[providerName]; ok {
+		if schema, ok := providerSchema.ResourceSchemas[resourceType]; ok {
+			return schema.Block
+		}
+		return nil
+	}
+	for _, providerName := range slices.Sorted(maps.Keys(providerSchemas.schemas.Schemas)) {
+		if schema, ok := providerSchemas.schemas.Schemas[providerName].ResourceSchemas[resourceType]; ok {
+			return schema.Block
+		}
+	}
+	return nil
+}
+
+// UnknownResourceTypes returns the resource types of the imports which none of
+// the providers has a schema for, and whose configuration is therefore not pruned
+func (providerSchemas ProviderSchemas) UnknownResourceTypes(imports TerraformImports) []string {
+	var unknownResourceTypes []string
+	for _, terraformImport := range imports {
+		address, err := parser.ParseAddress(terraformImport.ResourceAddress)
+		if err != nil || slices.Contains(unknownResourceTypes, address.Type) {
+			continue
+		}
+		if providerSchemas.lookup(terraformImport.providerName, address.Type) == nil {
+			unknownResourceTypes = append(unknownResourceTypes, address.Type)
+		}
+	}
+	slices.Sort(unknownResourceTypes)
+	return unknownResourceTypes
+}
+
+// appendConfigurableAttributeValues appends the values of the attributes which
+// can be configured according to the schema to the body. Computed only
+// attributes are left out and sensitive ones are replaced by a comment, so that
+// secrets do not end up in the code base. Values which the state marks as
+// sensitive are treated the same way.
+func appendConfigurableAttributeValues(body *hclwrite.Body, values map[string]any, sensitiveValues map[string]any, schema *tfjson.SchemaBlock, excludedNames []string) {
+	for _, name := range slices.Sorted(maps.Keys(schema.Attributes)) {
+		attribute := schema.Attributes[name]
+		if slices.Contains(excludedNames, name) || !(attribute.Required || attribute.Optional) || isEmptyValue(values[name]) {
+			continue
+		}
+		if isSensitiveAttribute(attribute) || containsSensitiveValue(sensitiveValues[name]) {
+			appendSensitiveComment(body, name)
+			continue
+		}
+		body.SetAttributeRaw(name, tokensForJsonValue(values[name]))
+	}
+	for _, name := range slices.Sorted(maps.Keys(schema.NestedBlocks)) {
+		nestedBlockSchema := schema.NestedBlocks[name]
+		if sensitiveValues[name] == true && !isEmptyValue(values[name]) {
+			appendSensitiveComment(body, name)
+			continue
+		}
+		switch value := values[name].(type) {
+		case []any:
+			for i, element := range value {
+				if element, ok := element.(map[string]any); ok {
+					body.AppendNewline()
+					nestedBlock := body.AppendNewBlock(name, nil)
+					appendConfigurableAttributeValues(nestedBlock.Body(), element, nestedSensitiveValues(sensitiveValues[name], i), nestedBlockSchema.Block, nil)
+				}
+			}
+		case map[string]any:
+			nestedSensitive, _ := sensitiveValues[name].(map[string]any)
+			if nestedBlockSchema.NestingMode != tfjson.SchemaNestingModeMap {
+				body.AppendNewline()
+				nestedBlock := body.AppendNewBlock(name, nil)
+				appendConfigurableAttributeValues(nestedBlock.Body(), value, nestedSensitive, nestedBlockSchema.Block, nil)
+				continue
+			}
+			for _, key := range slices.Sorted(maps.Keys(value)) {
+				if element, ok := value[key].(map[string]any); ok {
+					body.AppendNewline()
+					nestedBlock := body.AppendNewBlock(name, []string{key})
+					elementSensitive, _ := nestedSensitive[key].(map[string]any)
+					appendConfigurableAttributeValues(nestedBlock.Body(), element, elementSensitive, nestedBlockSchema.Block, nil)
+				}
+			}
+		}
+	}
+}
+
+// isSensitiveAttribute reports whether the attribute or any attribute of its
+// nested type is sensitive. Nested attributes are rendered as a whole, so a
+// single sensitive member leaves out the whole attribute.
+func isSensitiveAttribute(attribute *tfjson.SchemaAttribute) bool {
+	if attribute.Sensitive {
+		return true
+	}
+	if attribute.AttributeNestedType == nil {
+		return false
+	}
+	for _, nestedAttribute := range attribute.AttributeNestedType.Attributes {
+		if isSensitiveAttribute(nestedAttribute) {
+			return true
+		}
+	}
+	return false
+}
+
+func isEmptyValue(value any) bool {
+	switch value := value.(type) {
+	case nil:
+		return true
+	case []any:
+		return len(value) == 0
+	case map[string]any:
+		return len(value) == 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,111 @@
+package tfimportgen
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/kishaningithub/tf-import-gen/pkg/internal/parser"
+)
+
+// Summary is an overview of the imports for sanity checking a migration
+type Summary struct {
+	Total int
+	// Modules are the number of imports per module path without instance keys,
+	// in which the root module is empty
+	Modules []Count
+	// Types are the number of imports per resource type
+	Types []Count
+	// Unsupported are the addresses of the resources which do not support import
+	Unsupported []string
+	// Unresolved are the addresses of the resources whose import identifier
+	// could not be computed
+	Unresolved []string
+	// DefaultIDTypes are the resource types without an id rule, which are
+	// imported using their id attribute
+	DefaultIDTypes []string
+}
+
+// Count is the number of imports of a module or resource type
+type Count struct {
+	Name  string
+	Count int
+}
+
+// Summary summarizes the imports
+func (terraformImports TerraformImports) Summary() Summary {
+	summary := Summary{Total: len(terraformImports)}
+	modules := make(map[string]int)
+	types := make(map[string]int)
+	defaultIDTypes := make(map[string]bool)
+	for _, terraformImport := range terraformImports {
+		var modulePath, resourceType string
+		if address, err := parser.ParseAddress(terraformImport.ResourceAddress); err == nil {
+			modulePath = address.WithoutKeys().ModulePath()
+			resourceType = address.Type
+		}
+		modules[modulePath]++
+		types[resourceType]++
+		if !terraformImport.SupportsImport {
+			summary.Unsupported = append(summary.Unsupported, terraformImport.ResourceAddress)
+		} else if len(terraformImport.Diagnostics) > 0 {
+			summary.Unresolved = append(summary.Unresolved, terraformImport.ResourceAddress)
+		}
+		if terraformImport.SupportsImport && terraformImport.DefaultIDRule && len(resourceType) > 0 {
+			defaultIDTypes[resourceType] = true
+		}
+	}
+	summary.Modules = sortedCounts(modules)
+	summary.Types = sortedCounts(types)
+	summary.DefaultIDTypes = slices.Sorted(maps.Keys(defaultIDTypes))
+	return summary
+}
+
+func sortedCounts(counts map[string]int) []Count {
+	var sorted []Count
+	for _, name := range slices.Sorted(maps.Keys(counts)) {
+		sorted = append(sorted, Count{Name: name, Count: counts[name]})
+	}
+	return sorted
+}
+
+func (summary Summary) String() string {
+	var summaryStr strings.Builder
+	summaryStr.WriteString(fmt.Sprintf("%d imports\n", summary.Total))
+	writeCounts(&summaryStr, "Imports per module", summary.Modules, "(root module)")
+	writeCounts(&summaryStr, "Imports per resource type", summary.Types, "(unknown)")
+	writeList(&summaryStr, "Resources which do not support import", summary.Unsupported)
+	writeList(&summaryStr, "Resources whose import id could not be computed", summary.Unresolved)
+	writeList(&summaryStr, "Resource types without an id rule, imported using their id attribute", summary.DefaultIDTypes)
+	return summaryStr.String()
+}
+
+func writeCounts(summaryStr *strings.Builder, title string, counts []Count, emptyName string) {
+	if len(counts) == 0 {
+		return
+	}
+	names := make([]string, len(counts))
+	nameWidth := 0
+	for i, count := range counts {
+		names[i] = count.Name
+		if len(names[i]) == 0 {
+			names[i] = emptyName
+		}
+		nameWidth = max(nameWidth, len(names[i]))
+	}
+	summaryStr.WriteString(fmt.Sprintf("\n%s:\n", title))
+	for i, count := range counts {
+		summaryStr.WriteString(fmt.Sprintf("  %-*s %d\n", nameWidth, names[i], count.Count))
+	}
+}
+
+func writeList(summaryStr *strings.Builder, title string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	summaryStr.WriteString(fmt.Sprintf("\n%s:\n", title))
+	for _, value := range values {
+		summaryStr.WriteString(fmt.Sprintf("  %s\n", value))
+	}
+}
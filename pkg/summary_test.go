@@ -0,0 +1,81 @@
+package tfimportgen_test
+
+import (
+	"testing"
+
+	tfimportgen "github.com/kishaningithub/tf-import-gen/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImports_ShouldSummarize(t *testing.T) {
+	imports := tfimportgen.TerraformImports{
+		{ResourceAddress: "aws_iam_role.a", ResourceID: "a", SupportsImport: true, DefaultIDRule: true},
+		{ResourceAddress: `module.app["x"].aws_iam_role.b`, ResourceID: "b", SupportsImport: true, DefaultIDRule: true},
+		{ResourceAddress: `module.app["y"].aws_iam_role_policy_attachment.c`, ResourceID: "c/arn", SupportsImport: true},
+		{ResourceAddress: "aws_iam_policy_attachment.d", ResourceID: "d"},
+		{
+			ResourceAddress: "mycorp_dns_record.e",
+			SupportsImport:  true,
+			Diagnostics: []tfimportgen.Diagnostic{
+				{Kind: tfimportgen.DiagnosticMissingAttribute, Address: "mycorp_dns_record.e", Attribute: "zone"},
+			},
+		},
+	}
+
+	summary := imports.Summary()
+
+	require.Equal(t, tfimportgen.Summary{
+		Total: 5,
+		Modules: []tfimportgen.Count{
+			{Name: "", Count: 3},
+			{Name: "module.app", Count: 2},
+		},
+		Types: []tfimportgen.Count{
+			{Name: "aws_iam_policy_attachment", Count: 1},
+			{Name: "aws_iam_role", Count: 2},
+			{Name: "aws_iam_role_policy_attachment", Count: 1},
+			{Name: "mycorp_dns_record", Count: 1},
+		},
+		Unsupported:    []string{"aws_iam_policy_attachment.d"},
+		Unresolved:     []string{"mycorp_dns_record.e"},
+		DefaultIDTypes: []string{"aws_iam_role"},
+	}, summary)
+	require.Equal(t, `5 imports
+
+Imports per module:
+  (root module) 3
+  module.app    2
+
+Imports per resource type:
+  aws_iam_policy_attachment      1
+  aws_iam_role                   2
+  aws_iam_role_policy_attachment 1
+  mycorp_dns_record              1
+
+Resources which do not support import:
+  aws_iam_policy_attachment.d
+
+Resources whose import id could not be computed:
+  mycorp_dns_record.e
+
+Resource types without an id rule, imported using their id attribute:
+  aws_iam_role
+`, summary.String())
+}
+
+func TestImports_ShouldNotSummarizeResourcesImportedByTheirIdentityAsUnresolved(t *testing.T) {
+	imports := tfimportgen.TerraformImports{
+		{
+			ResourceAddress: "aws_iam_role_policy_attachment.test",
+			SupportsImport:  true,
+			Diagnostics: []tfimportgen.Diagnostic{
+				{Kind: tfimportgen.DiagnosticMissingAttribute, Address: "aws_iam_role_policy_attachment.test", Attribute: "policy_arn"},
+			},
+			Identity: map[string]any{"role": "test-role", "policy_arn": "arn:aws:iam::aws:policy/ReadOnlyAccess"},
+		},
+	}
+
+	require.Empty(t, imports.ForFormat(tfimportgen.FormatIdentity).Summary().Unresolved)
+	require.Equal(t, []string{"aws_iam_role_policy_attachment.test"}, imports.ForFormat(tfimportgen.FormatImport).Summary().Unresolved)
+	require.Len(t, imports[0].Diagnostics, 1)
+}
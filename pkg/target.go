@@ -0,0 +1,324 @@
+package tfimportgen
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/kishaningithub/tf-import-gen/pkg/internal/parser"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// TargetConfiguration is the terraform configuration of the destination code
+// base, which the import targets are validated against
+type TargetConfiguration struct {
+	root *targetModule
+}
+
+// keyStyle is the kind of instance keys a resource or module block produces
+type keyStyle string
+
+const (
+	keyStyleNone    keyStyle = "none"
+	keyStyleCount   keyStyle = "count"
+	keyStyleForEach keyStyle = "for_each"
+)
+
+func keyStyleOf(key any) keyStyle {
+	switch key.(type) {
+	case nil:
+		return keyStyleNone
+	case int:
+		return keyStyleCount
+	default:
+		return keyStyleForEach
+	}
+}
+
+func (style keyStyle) String() string {
+	switch style {
+	case keyStyleCount:
+		return "uses count"
+	case keyStyleForEach:
+		return "uses for_each"
+	default:
+		return "has neither count nor for_each"
+	}
+}
+
+type targetModule struct {
+	// resources are the key styles of the managed resources by type.name
+	resources map[string]keyStyle
+	// moduleCalls are the module blocks by name
+	moduleCalls map[string]targetModuleCall
+}
+
+type targetModuleCall struct {
+	keyStyle keyStyle
+	source   string
+	// module is nil when the source is not a local directory
+	module *targetModule
+}
+
+var targetFileSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "resource", LabelNames: []string{"type", "name"}},
+		{Type: "module", LabelNames: []string{"name"}},
+	},
+}
+
+var targetBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "count"},
+		{Name: "for_each"},
+		{Name: "source"},
+	},
+}
+
+// LoadTargetConfiguration parses the terraform configuration in the directory,
+// following the module blocks whose source is a local directory
+func LoadTargetConfiguration(dir string) (TargetConfiguration, error) {
+	loader := targetLoader{parser: hclparse.NewParser(), modules: make(map[string]*targetModule)}
+	root, err := loader.load(dir)
+	if err != nil {
+		return TargetConfiguration{}, err
+	}
+	return TargetConfiguration{root: root}, nil
+}
+
+type targetLoader struct {
+	parser *hclparse.Parser
+	// modules are the modules loaded so far by their directory, which also
+	// stops module sources referring to each other from looping forever
+	modules map[string]*targetModule
+}
+
+func (loader targetLoader) load(dir string) (*targetModule, error) {
+	dir = filepath.Clean(dir)
+	if module, ok := loader.modules[dir]; ok {
+		return module, nil
+	}
+	module := &targetModule{resources: make(map[string]keyStyle), moduleCalls: make(map[string]targetModuleCall)}
+	loader.modules[dir] = module
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target configuration: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		var file *hcl.File
+		var diags hcl.Diagnostics
+		switch {
+		case strings.HasSuffix(entry.Name(), ".tf"):
+			file, diags = loader.parser.ParseHCLFile(filepath.Join(dir, entry.Name()))
+		case strings.HasSuffix(entry.Name(), ".tf.json"):
+			file, diags = loader.parser.ParseJSONFile(filepath.Join(dir, entry.Name()))
+		default:
+			continue
+		}
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("invalid target configuration: %w", diags)
+		}
+		if err := loader.loadBlocks(dir, file, module); err != nil {
+			return nil, err
+		}
+	}
+	return module, nil
+}
+
+func (loader targetLoader) loadBlocks(dir string, file *hcl.File, module *targetModule) error {
+	content, _, diags := file.Body.PartialContent(targetFileSchema)
+	if diags.HasErrors() {
+		return fmt.Errorf("invalid target configuration: %w", diags)
+	}
+	for _, block := range content.Blocks {
+		blockContent, _, diags := block.Body.PartialContent(targetBlockSchema)
+		if diags.HasErrors() {
+			return fmt.Errorf("invalid target configuration: %w", diags)
+		}
+		style := keyStyleNone
+		if _, ok := blockContent.Attributes["count"]; ok {
+			style = keyStyleCount
+		} else if _, ok := blockContent.Attributes["for_each"]; ok {
+			style = keyStyleForEach
+		}
+		if block.Type == "resource" {
+			module.resources[block.Labels[0]+"."+block.Labels[1]] = style
+			continue
+		}
+		moduleCall := targetModuleCall{keyStyle: style}
+		if sourceAttribute, ok := blockContent.Attributes["source"]; ok {
+			// the source must be a literal string, so it evaluates without a context
+			source, diags := sourceAttribute.Expr.Value(nil)
+			if !diags.HasErrors() && source.Type() == cty.String && source.IsKnown() && !source.IsNull() {
+				moduleCall.source = source.AsString()
+			}
+		}
+		if strings.HasPrefix(moduleCall.source, "./") || strings.HasPrefix(moduleCall.source, "../") {
+			childModule, err := loader.load(filepath.Join(dir, filepath.FromSlash(moduleCall.source)))
+			if err != nil {
+				return err
+			}
+			moduleCall.module = childModule
+		}
+		module.moduleCalls[block.Labels[0]] = moduleCall
+	}
+	return nil
+}
+
+// TargetMismatchKind is the kind of mismatch between an import target and the
+// target configuration
+type TargetMismatchKind string
+
+const (
+	// TargetMismatchMissingModule is reported when a module of the import
+	// target has no module block
+	TargetMismatchMissingModule TargetMismatchKind = "missing_module"
+	// TargetMismatchMissingResource is reported when the import target has no
+	// resource block
+	TargetMismatchMissingResource TargetMismatchKind = "missing_resource"
+	// TargetMismatchKeyStyle is reported when an instance key of the import
+	// target does not match the count or for_each of its module or resource
+	// block, e.g. a numeric key for a resource using for_each
+	TargetMismatchKeyStyle TargetMismatchKind = "key_style"
+	// TargetMismatchUnverifiedModule is reported when a module of the import
+	// target has a source which is not a local directory, so that the import
+	// target cannot be validated. It is informational rather than a mismatch.
+	TargetMismatchUnverifiedModule TargetMismatchKind = "unverified_module"
+)
+
+// TargetMismatch describes why an import target does not match the target
+// configuration
+type TargetMismatch struct {
+	Kind TargetMismatchKind `json:"kind"`
+	// Address is the address the resource is imported to
+	Address string `json:"address"`
+	// Block is the module or resource block the mismatch is about, e.g.
+	// module.network.aws_subnet.private
+	Block string `json:"block"`
+	// Detail is the source of the module for TargetMismatchUnverifiedModule,
+	// and how the block produces instance keys for TargetMismatchKeyStyle
+	Detail string `json:"detail,omitempty"`
+}
+
+func (mismatch TargetMismatch) String() string {
+	switch mismatch.Kind {
+	case TargetMismatchMissingModule, TargetMismatchMissingResource:
+		return fmt.Sprintf("import target %s: %s is not declared in the target configuration", mismatch.Address, mismatch.Block)
+	case TargetMismatchKeyStyle:
+		return fmt.Sprintf("import target %s: %s %s, which does not match the instance key", mismatch.Address, mismatch.Block, mismatch.Detail)
+	default:
+		return fmt.Sprintf("import target %s: %s has the source %q which is not a local directory and cannot be validated", mismatch.Address, mismatch.Block, mismatch.Detail)
+	}
+}
+
+// Validate reports the import targets which have no module or resource block in
+// the target configuration, or whose instance keys do not match the count or
+// for_each of their blocks. Resources which do not support import are skipped.
+func (configuration TargetConfiguration) Validate(imports TerraformImports) []TargetMismatch {
+	var mismatches []TargetMismatch
+	for _, terraformImport := range imports {
+		if !terraformImport.SupportsImport {
+			continue
+		}
+		address, err := parser.ParseAddress(terraformImport.ResourceAddress)
+		if err != nil || address.IsModule() {
+			continue
+		}
+		if mismatch, ok := configuration.validate(address); ok {
+			mismatch.Address = terraformImport.ResourceAddress
+			mismatches = append(mismatches, mismatch)
+		}
+	}
+	return mismatches
+}
+
+func (configuration TargetConfiguration) validate(address parser.Address) (TargetMismatch, bool) {
+	module := configuration.root
+	var block parser.Address
+	for _, moduleInstance := range address.Module {
+		block.Module = append(block.Module, parser.ModuleInstance{Name: moduleInstance.Name})
+		moduleCall, ok := module.moduleCalls[moduleInstance.Name]
+		if !ok {
+			return TargetMismatch{Kind: TargetMismatchMissingModule, Block: block.ModulePath()}, true
+		}
+		if moduleCall.keyStyle != keyStyleOf(moduleInstance.Key) {
+			return TargetMismatch{Kind: TargetMismatchKeyStyle, Block: block.ModulePath(), Detail: moduleCall.keyStyle.String()}, true
+		}
+		if moduleCall.module == nil {
+			return TargetMismatch{Kind: TargetMismatchUnverifiedModule, Block: block.ModulePath(), Detail: moduleCall.source}, true
+		}
+		module = moduleCall.module
+	}
+	block.Mode, block.Type, block.Name = address.Mode, address.Type, address.Name
+	style, ok := module.resources[address.Type+"."+address.Name]
+	if !ok {
+		return TargetMismatch{Kind: TargetMismatchMissingResource, Block: block.String()}, true
+	}
+	if style != keyStyleOf(address.Key) {
+		return TargetMismatch{Kind: TargetMismatchKeyStyle, Block: block.String(), Detail: style.String()}, true
+	}
+	return TargetMismatch{}, false
+}
+
+// targetResourceBlock is a resource block of the target configuration along
+// with the key styles of the module blocks it is nested in
+type targetResourceBlock struct {
+	address         parser.Address
+	moduleKeyStyles []keyStyle
+}
+
+// resourceBlocks returns the resource blocks of the root module and of the
+// modules with a local source, sorted by address
+func (configuration TargetConfiguration) resourceBlocks() []targetResourceBlock {
+	var resourceBlocks []targetResourceBlock
+	var appendResourceBlocks func(module *targetModule, modulePath []parser.ModuleInstance, moduleKeyStyles []keyStyle, parents []*targetModule)
+	appendResourceBlocks = func(module *targetModule, modulePath []parser.ModuleInstance, moduleKeyStyles []keyStyle, parents []*targetModule) {
+		// modules whose sources refer to each other would nest forever
+		if slices.Contains(parents, module) {
+			return
+		}
+		for _, resource := range slices.Sorted(maps.Keys(module.resources)) {
+			resourceType, name, _ := strings.Cut(resource, ".")
+			resourceBlocks = append(resourceBlocks, targetResourceBlock{
+				address:         parser.Address{Module: modulePath, Mode: "managed", Type: resourceType, Name: name},
+				moduleKeyStyles: moduleKeyStyles,
+			})
+		}
+		for _, name := range slices.Sorted(maps.Keys(module.moduleCalls)) {
+			moduleCall := module.moduleCalls[name]
+			if moduleCall.module != nil {
+				appendResourceBlocks(moduleCall.module,
+					append(slices.Clip(modulePath), parser.ModuleInstance{Name: name}),
+					append(slices.Clip(moduleKeyStyles), moduleCall.keyStyle),
+					append(slices.Clip(parents), module))
+			}
+		}
+	}
+	appendResourceBlocks(configuration.root, nil, nil, nil)
+	return resourceBlocks
+}
+
+// hasUnverifiedModule reports whether the module path passes through a module
+// whose source is not a local directory, so that its resources are unknown
+func (configuration TargetConfiguration) hasUnverifiedModule(modulePath []parser.ModuleInstance) bool {
+	module := configuration.root
+	for _, moduleInstance := range modulePath {
+		moduleCall, ok := module.moduleCalls[moduleInstance.Name]
+		if !ok {
+			return false
+		}
+		if moduleCall.module == nil {
+			return true
+		}
+		module = moduleCall.module
+	}
+	return false
+}
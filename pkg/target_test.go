@@ -0,0 +1,125 @@
+package tfimportgen_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tfimportgen "github.com/kishaningithub/tf-import-gen/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTargetConfiguration_Validate(t *testing.T) {
+	configuration, err := tfimportgen.LoadTargetConfiguration(filepath.FromSlash("testdata/target"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		address  string
+		expected []tfimportgen.TargetMismatch
+	}{
+		{name: "resource without count or for_each", address: "aws_s3_bucket.logs"},
+		{name: "resource with for_each", address: `aws_s3_bucket.this["assets"]`},
+		{name: "resource with count", address: "aws_instance.web[1]"},
+		{name: "resource in a local module", address: "module.network.aws_subnet.private[0]"},
+		{name: "resource in a local module with for_each", address: `module.regions["eu-west-1"].aws_vpc.main`},
+		{
+			name:    "missing resource",
+			address: "aws_s3_bucket.assets",
+			expected: []tfimportgen.TargetMismatch{
+				{Kind: tfimportgen.TargetMismatchMissingResource, Address: "aws_s3_bucket.assets", Block: "aws_s3_bucket.assets"},
+			},
+		},
+		{
+			name:    "missing resource in a module",
+			address: `module.regions["us-east-1"].aws_subnet.public[0]`,
+			expected: []tfimportgen.TargetMismatch{
+				{Kind: tfimportgen.TargetMismatchMissingResource, Address: `module.regions["us-east-1"].aws_subnet.public[0]`, Block: "module.regions.aws_subnet.public"},
+			},
+		},
+		{
+			name:    "missing module",
+			address: "module.storage.aws_s3_bucket.logs",
+			expected: []tfimportgen.TargetMismatch{
+				{Kind: tfimportgen.TargetMismatchMissingModule, Address: "module.storage.aws_s3_bucket.logs", Block: "module.storage"},
+			},
+		},
+		{
+			name:    "numeric key for a resource with for_each",
+			address: "aws_s3_bucket.this[0]",
+			expected: []tfimportgen.TargetMismatch{
+				{Kind: tfimportgen.TargetMismatchKeyStyle, Address: "aws_s3_bucket.this[0]", Block: "aws_s3_bucket.this", Detail: "uses for_each"},
+			},
+		},
+		{
+			name:    "no key for a resource with count",
+			address: "aws_instance.web",
+			expected: []tfimportgen.TargetMismatch{
+				{Kind: tfimportgen.TargetMismatchKeyStyle, Address: "aws_instance.web", Block: "aws_instance.web", Detail: "uses count"},
+			},
+		},
+		{
+			name:    "key for a module without count or for_each",
+			address: `module.network["a"].aws_vpc.main`,
+			expected: []tfimportgen.TargetMismatch{
+				{Kind: tfimportgen.TargetMismatchKeyStyle, Address: `module.network["a"].aws_vpc.main`, Block: "module.network", Detail: "has neither count nor for_each"},
+			},
+		},
+		{
+			name:    "module with a registry source",
+			address: "module.vpc.aws_vpc.this[0]",
+			expected: []tfimportgen.TargetMismatch{
+				{Kind: tfimportgen.TargetMismatchUnverifiedModule, Address: "module.vpc.aws_vpc.this[0]", Block: "module.vpc", Detail: "terraform-aws-modules/vpc/aws"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imports := tfimportgen.TerraformImports{{ResourceAddress: tt.address, ResourceID: "id", SupportsImport: true}}
+			require.Equal(t, tt.expected, configuration.Validate(imports))
+		})
+	}
+}
+
+func TestTargetConfiguration_ValidateShouldSkipResourcesWhichDoNotSupportImport(t *testing.T) {
+	configuration, err := tfimportgen.LoadTargetConfiguration(filepath.FromSlash("testdata/target"))
+	require.NoError(t, err)
+
+	imports := tfimportgen.TerraformImports{{ResourceAddress: "aws_iam_policy_attachment.test", ResourceID: "test"}}
+
+	require.Empty(t, configuration.Validate(imports))
+}
+
+func TestTargetMismatch_String(t *testing.T) {
+	tests := []struct {
+		mismatch tfimportgen.TargetMismatch
+		expected string
+	}{
+		{
+			mismatch: tfimportgen.TargetMismatch{Kind: tfimportgen.TargetMismatchMissingModule, Address: "module.storage.aws_s3_bucket.logs", Block: "module.storage"},
+			expected: "import target module.storage.aws_s3_bucket.logs: module.storage is not declared in the target configuration",
+		},
+		{
+			mismatch: tfimportgen.TargetMismatch{Kind: tfimportgen.TargetMismatchKeyStyle, Address: "aws_s3_bucket.this[0]", Block: "aws_s3_bucket.this", Detail: "uses for_each"},
+			expected: "import target aws_s3_bucket.this[0]: aws_s3_bucket.this uses for_each, which does not match the instance key",
+		},
+		{
+			mismatch: tfimportgen.TargetMismatch{Kind: tfimportgen.TargetMismatchUnverifiedModule, Address: "module.vpc.aws_vpc.this[0]", Block: "module.vpc", Detail: "terraform-aws-modules/vpc/aws"},
+			expected: `import target module.vpc.aws_vpc.this[0]: module.vpc has the source "terraform-aws-modules/vpc/aws" which is not a local directory and cannot be validated`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mismatch.Kind), func(t *testing.T) {
+			require.Equal(t, tt.expected, tt.mismatch.String())
+		})
+	}
+}
+
+func TestLoadTargetConfiguration_ShouldFailForInvalidConfiguration(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(`resource "aws_s3_bucket" {`), 0o644))
+
+	_, err := tfimportgen.LoadTargetConfiguration(dir)
+
+	require.ErrorContains(t, err, "invalid target configuration")
+}
@@ -0,0 +1,29 @@
+resource "aws_s3_bucket" "logs" {
+  bucket = "example-logs"
+}
+
+resource "aws_s3_bucket" "this" {
+  for_each = toset(["assets", "backups"])
+
+  bucket = each.key
+}
+
+resource "aws_instance" "web" {
+  count = 2
+
+  ami           = "ami-0123456789"
+  instance_type = "t3.micro"
+}
+
+module "network" {
+  source = "./modules/network"
+}
+
+module "regions" {
+  source   = "./modules/network"
+  for_each = toset(["eu-west-1", "us-east-1"])
+}
+
+module "vpc" {
+  source = "terraform-aws-modules/vpc/aws"
+}
@@ -1,25 +1,165 @@
 package tfimportgen
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"github.com/kishaningithub/tf-import-gen/pkg/internal/parser"
 	"io"
+	"slices"
 )
 
-func GenerateImports(stateJsonReader io.Reader, addresses []string) (TerraformImports, error) {
-	resources, err := parser.NewTerraformStateJsonParser(stateJsonReader).Parse()
+func GenerateImports(stateJsonReader io.Reader, addresses []string, opts ...Option) (TerraformImports, error) {
+	return GenerateImportsFromStates([]StateInput{{Reader: stateJsonReader}}, addresses, opts...)
+}
+
+// StateInput is the output of `terraform show -json` for a state or a plan, or
+// a raw state file
+type StateInput struct {
+	Reader io.Reader
+	// ModulePrefix is the address of the module, e.g. module.network, into which
+	// the resources of this input are imported. It is empty for the root module.
+	ModulePrefix string
+}
+
+// ParseError is returned when an input is neither a state nor a plan which can
+// be parsed
+type ParseError struct {
+	Err error
+}
+
+func (parseErr ParseError) Error() string {
+	return parseErr.Err.Error()
+}
+
+func (parseErr ParseError) Unwrap() error {
+	return parseErr.Err
+}
+
+// GenerateImportsFromStates parses every input independently and merges their
+// imports, which allows consolidating multiple workspaces into modules of a
+// single root configuration
+func GenerateImportsFromStates(inputs []StateInput, addresses []string, opts ...Option) (TerraformImports, error) {
+	options := newOptions(opts)
+	for _, plannedAction := range options.plannedActions {
+		if !slices.Contains(parser.PlannedActions, plannedAction) {
+			return nil, fmt.Errorf("unsupported planned action %q, supported actions are %v", plannedAction, parser.PlannedActions)
+		}
+	}
+
+	registry, err := defaultIDRuleRegistry.with(options.idRules)
+	if err != nil {
+		return nil, err
+	}
+	keyMappings, err := options.keyMappings.compile()
 	if err != nil {
 		return nil, err
 	}
 
-	if addresses != nil {
-		resources = resources.FilterByAddresses(addresses)
+	var imports TerraformImports
+	// collisions span all inputs, as they are merged into a single configuration
+	var collisions keyCollisions
+	for _, input := range inputs {
+		inputImports, err := generateImportsForInput(input, addresses, options, registry, keyMappings, &collisions)
+		if err != nil {
+			return nil, err
+		}
+		imports = append(imports, inputImports...)
+	}
+	if err := collisions.err(); err != nil {
+		return nil, err
+	}
+
+	return imports, nil
+}
+
+func generateImportsForInput(input StateInput, addresses []string, options options, registry idRuleRegistry, keyMappings compiledKeyMappings, collisions *keyCollisions) (TerraformImports, error) {
+	if len(input.ModulePrefix) > 0 {
+		modulePrefix, err := parser.ParseAddress(input.ModulePrefix)
+		if err != nil || !modulePrefix.IsModule() {
+			return nil, fmt.Errorf("invalid module prefix %q, expected a module address like module.example", input.ModulePrefix)
+		}
+	}
+
+	// the input is read upfront, so that failing to read it is not mistaken for
+	// an input which cannot be parsed
+	inputBytes, err := io.ReadAll(input.Reader)
+	if err != nil {
+		return nil, err
+	}
+	stateParser, err := parser.NewTerraformStateParser(bytes.NewReader(inputBytes))
+	if err != nil {
+		return nil, ParseError{Err: err}
+	}
+	// a state does not record planned actions, so every resource would be left out
+	if _, isPlan := stateParser.(parser.TerraformPlanJsonParser); len(options.plannedActions) > 0 && !isPlan {
+		return nil, errors.New("planned actions can only be selected from a plan, but the input is a state")
+	}
+	resources, err := stateParser.Parse()
+	if err != nil {
+		return nil, ParseError{Err: err}
+	}
+
+	resources, err = selectResources(resources, addresses, options)
+	if err != nil {
+		return nil, err
 	}
 
 	var imports TerraformImports
 	for _, resource := range resources {
-		terraformImport := computeTerraformImportForResource(resource)
+		terraformImport := computeTerraformImportForResource(resource, registry)
+		if options.attributeValues {
+			terraformImport.AttributeValues = resource.AttributeValues
+			terraformImport.sensitiveValues = resource.SensitiveValues
+			terraformImport.providerName = resource.Provider.Name
+			terraformImport.schema = options.providerSchemas.lookup(resource.Provider.Name, resource.Type)
+		}
+		rekeyedAddress, rekeyed, err := keyMappings.apply(resource)
+		if err != nil {
+			return nil, err
+		}
+		destinationAddress := options.addressMappings.apply(rekeyedAddress)
+		if len(input.ModulePrefix) > 0 {
+			destinationAddress = fmt.Sprintf("%s.%s", input.ModulePrefix, destinationAddress)
+		}
+		collisions.add(resource.Address, destinationAddress, rekeyed)
+		if destinationAddress != terraformImport.ResourceAddress {
+			terraformImport.SourceAddress = terraformImport.ResourceAddress
+			terraformImport.ResourceAddress = destinationAddress
+		}
 		imports = append(imports, terraformImport)
 	}
 
 	return imports, nil
 }
+
+func selectResources(resources parser.TerraformResources, addresses []string, options options) (parser.TerraformResources, error) {
+	var err error
+	if addresses != nil && options.regexAddresses {
+		resources, err = resources.FilterByAddressRegexps(addresses)
+	} else if addresses != nil {
+		resources, err = resources.FilterByAddresses(addresses)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if options.regexAddresses {
+		resources, err = resources.ExcludeByAddressRegexps(options.excludedAddresses)
+	} else {
+		resources, err = resources.ExcludeByAddresses(options.excludedAddresses)
+	}
+	if err != nil {
+		return nil, err
+	}
+	resources = resources.ExcludeByTypes(options.excludedTypes)
+
+	if len(options.providers) > 0 {
+		resources = resources.FilterByProviders(options.providers)
+	}
+
+	if len(options.plannedActions) > 0 {
+		resources = resources.FilterByPlannedActions(options.plannedActions)
+	}
+	return resources, nil
+}
@@ -2,12 +2,14 @@ package tfimportgen_test
 
 import (
 	"bytes"
+	"errors"
 	tfimportgen "github.com/kishaningithub/tf-import-gen/pkg"
 	"github.com/stretchr/testify/require"
 	"io"
 	"os"
 	"path/filepath"
 	"testing"
+	"testing/iotest"
 )
 
 func Test_GenerateImports_ShouldGenerateImportsForAllResourcesWhenNoFiltersAreGiven(t *testing.T) {
@@ -24,11 +26,13 @@ func Test_GenerateImports_ShouldGenerateImportsForAllResourcesWhenNoFiltersAreGi
 					ResourceAddress: "aws_glue_catalog_database.test_db",
 					ResourceID:      "id_test_db",
 					SupportsImport:  true,
+					DefaultIDRule:   true,
 				},
 				{
 					ResourceAddress: "aws_iam_instance_profile.test_instance_profile",
 					ResourceID:      "id_test_instance_profile",
 					SupportsImport:  true,
+					DefaultIDRule:   true,
 				},
 			},
 		},
@@ -40,11 +44,13 @@ func Test_GenerateImports_ShouldGenerateImportsForAllResourcesWhenNoFiltersAreGi
 					ResourceAddress: "module.test_mwaa.aws_iam_policy.test_mwaa_permissions",
 					ResourceID:      "id_test_mwaa_permissions",
 					SupportsImport:  true,
+					DefaultIDRule:   true,
 				},
 				{
 					ResourceAddress: "module.test_mwaa.aws_mwaa_environment.test_airflow_env",
 					ResourceID:      "id_test_airflow_env",
 					SupportsImport:  true,
+					DefaultIDRule:   true,
 				},
 			},
 		},
@@ -56,11 +62,13 @@ func Test_GenerateImports_ShouldGenerateImportsForAllResourcesWhenNoFiltersAreGi
 					ResourceAddress: "module.test_mwaa.nested1.nested2.aws_iam_policy.test_mwaa_permissions",
 					ResourceID:      "id_test_mwaa_permissions",
 					SupportsImport:  true,
+					DefaultIDRule:   true,
 				},
 				{
 					ResourceAddress: "module.test_mwaa.nested1.nested2.aws_mwaa_environment.test_airflow_env",
 					ResourceID:      "id_test_airflow_env",
 					SupportsImport:  true,
+					DefaultIDRule:   true,
 				},
 			},
 		},
@@ -119,11 +127,13 @@ func Test_GenerateImports_ShouldGenerateImportsForResourcesForGivenAddress(t *te
 					ResourceAddress: "module.test_mwaa.aws_iam_policy.test_mwaa_permissions",
 					ResourceID:      "id_test_mwaa_permissions",
 					SupportsImport:  true,
+					DefaultIDRule:   true,
 				},
 				{
 					ResourceAddress: "module.test_mwaa.aws_mwaa_environment.test_airflow_env",
 					ResourceID:      "id_test_airflow_env",
 					SupportsImport:  true,
+					DefaultIDRule:   true,
 				},
 			},
 		},
@@ -135,6 +145,7 @@ func Test_GenerateImports_ShouldGenerateImportsForResourcesForGivenAddress(t *te
 					ResourceAddress: "aws_glue_catalog_database.test_db",
 					ResourceID:      "id_test_db",
 					SupportsImport:  true,
+					DefaultIDRule:   true,
 				},
 			},
 		},
@@ -195,16 +206,19 @@ func Test_GenerateImports_ShouldGenerateImportsForResourcesForMultipleAddresses(
 					ResourceAddress: "aws_glue_catalog_database.test_db",
 					ResourceID:      "id_test_db",
 					SupportsImport:  true,
+					DefaultIDRule:   true,
 				},
 				{
 					ResourceAddress: "module.test_mwaa.aws_iam_policy.test_mwaa_permissions",
 					ResourceID:      "id_test_mwaa_permissions",
 					SupportsImport:  true,
+					DefaultIDRule:   true,
 				},
 				{
 					ResourceAddress: "module.test_mwaa.aws_mwaa_environment.test_airflow_env",
 					ResourceID:      "id_test_airflow_env",
 					SupportsImport:  true,
+					DefaultIDRule:   true,
 				},
 			},
 		},
@@ -240,6 +254,7 @@ func Test_GenerateImports_ShouldNotGenerateDuplicateImportsForResourcesForMultip
 					ResourceAddress: "aws_glue_catalog_database.test_db",
 					ResourceID:      "id_test_db",
 					SupportsImport:  true,
+					DefaultIDRule:   true,
 				},
 			},
 		},
@@ -260,3 +275,609 @@ func Test_GenerateImports_ShouldNotGenerateDuplicateImportsForResourcesForMultip
 		})
 	}
 }
+
+func Test_GenerateImports_ShouldGenerateImportsFromTerraformStateFile(t *testing.T) {
+	stateFile, err := os.Open(filepath.FromSlash("testdata/state_file_resources_in_root_and_child_modules.json"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = stateFile.Close()
+	})
+
+	actual, err := tfimportgen.GenerateImports(stateFile, nil)
+
+	require.NoError(t, err)
+	expectedImports := tfimportgen.TerraformImports{
+		{
+			ResourceAddress: "aws_glue_catalog_database.test_db",
+			ResourceID:      "id_test_db",
+			SupportsImport:  true,
+			DefaultIDRule:   true,
+		},
+		{
+			ResourceAddress: "module.test_mwaa.aws_iam_policy.test_mwaa_permissions",
+			ResourceID:      "id_test_mwaa_permissions",
+			SupportsImport:  true,
+			DefaultIDRule:   true,
+		},
+		{
+			ResourceAddress: "module.test_mwaa.aws_s3_bucket.logs[0]",
+			ResourceID:      "id_logs_0",
+			SupportsImport:  true,
+			DefaultIDRule:   true,
+		},
+		{
+			ResourceAddress: "module.test_mwaa.aws_s3_bucket.logs[1]",
+			ResourceID:      "id_logs_1",
+			SupportsImport:  true,
+			DefaultIDRule:   true,
+		},
+		{
+			ResourceAddress: `module.team["data"].module.storage.aws_s3_bucket.this["raw"]`,
+			ResourceID:      "id_raw",
+			SupportsImport:  true,
+			DefaultIDRule:   true,
+		},
+	}
+	require.Equal(t, expectedImports, actual)
+}
+
+func Test_GenerateImports_ShouldGenerateImportsForResourcesInPlanForGivenPlannedActions(t *testing.T) {
+	tests := []struct {
+		name           string
+		plannedActions []string
+		expected       tfimportgen.TerraformImports
+	}{
+		{
+			name:           "no planned actions selects whole prior state",
+			plannedActions: nil,
+			expected: tfimportgen.TerraformImports{
+				{
+					ResourceAddress: "aws_glue_catalog_database.test_db",
+					ResourceID:      "id_test_db",
+					SupportsImport:  true,
+					DefaultIDRule:   true,
+				},
+				{
+					ResourceAddress: "aws_iam_instance_profile.test_instance_profile",
+					ResourceID:      "id_test_instance_profile",
+					SupportsImport:  true,
+					DefaultIDRule:   true,
+				},
+				{
+					ResourceAddress: "module.test_mwaa.aws_iam_policy.test_mwaa_permissions",
+					ResourceID:      "id_test_mwaa_permissions",
+					SupportsImport:  true,
+					DefaultIDRule:   true,
+				},
+				{
+					ResourceAddress: "module.test_mwaa.aws_mwaa_environment.test_airflow_env",
+					ResourceID:      "id_test_airflow_env",
+					SupportsImport:  true,
+					DefaultIDRule:   true,
+				},
+			},
+		},
+		{
+			name:           "delete and replace",
+			plannedActions: []string{"delete", "replace"},
+			expected: tfimportgen.TerraformImports{
+				{
+					ResourceAddress: "aws_iam_instance_profile.test_instance_profile",
+					ResourceID:      "id_test_instance_profile",
+					SupportsImport:  true,
+					DefaultIDRule:   true,
+				},
+				{
+					ResourceAddress: "module.test_mwaa.aws_iam_policy.test_mwaa_permissions",
+					ResourceID:      "id_test_mwaa_permissions",
+					SupportsImport:  true,
+					DefaultIDRule:   true,
+				},
+			},
+		},
+		{
+			name:           "no-op",
+			plannedActions: []string{"no-op"},
+			expected: tfimportgen.TerraformImports{
+				{
+					ResourceAddress: "aws_glue_catalog_database.test_db",
+					ResourceID:      "id_test_db",
+					SupportsImport:  true,
+					DefaultIDRule:   true,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			planJsonFile, err := os.Open(filepath.FromSlash("testdata/plan.json"))
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				_ = planJsonFile.Close()
+			})
+
+			actual, err := tfimportgen.GenerateImports(planJsonFile, nil, tfimportgen.WithPlannedActions(tt.plannedActions...))
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func Test_GenerateImports_ShouldRejectUnsupportedPlannedActions(t *testing.T) {
+	_, err := tfimportgen.GenerateImports(bytes.NewBufferString("{}"), nil, tfimportgen.WithPlannedActions("create"))
+
+	require.EqualError(t, err, `unsupported planned action "create", supported actions are [no-op update delete replace forget]`)
+}
+
+func Test_GenerateImports_ShouldRejectPlannedActionsForStates(t *testing.T) {
+	stateJsonFile, err := os.Open(filepath.FromSlash("testdata/only_root_resources.json"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = stateJsonFile.Close()
+	})
+
+	_, err = tfimportgen.GenerateImports(stateJsonFile, nil, tfimportgen.WithPlannedActions("delete"))
+
+	require.EqualError(t, err, "planned actions can only be selected from a plan, but the input is a state")
+}
+
+func Test_GenerateImports_ShouldRewriteAddressesUsingAddressMappings(t *testing.T) {
+	stateJsonFile, err := os.Open(filepath.FromSlash("testdata/resources_in_root_and_child_modules.json"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = stateJsonFile.Close()
+	})
+
+	actual, err := tfimportgen.GenerateImports(stateJsonFile, []string{"module.test_mwaa", "aws_glue_catalog_database.test_db"}, tfimportgen.WithAddressMappings(
+		tfimportgen.AddressMapping{From: "module.test_mwaa", To: "module.airflow"},
+		tfimportgen.AddressMapping{From: "module.test_mwaa.aws_iam_policy.test_mwaa_permissions", To: "aws_iam_policy.airflow"},
+	))
+
+	require.NoError(t, err)
+	expectedImports := tfimportgen.TerraformImports{
+		{
+			ResourceAddress: "aws_glue_catalog_database.test_db",
+			ResourceID:      "id_test_db",
+			SupportsImport:  true,
+			DefaultIDRule:   true,
+		},
+		{
+			ResourceAddress: "aws_iam_policy.airflow",
+			ResourceID:      "id_test_mwaa_permissions",
+			SupportsImport:  true,
+			DefaultIDRule:   true,
+			SourceAddress:   "module.test_mwaa.aws_iam_policy.test_mwaa_permissions",
+		},
+		{
+			ResourceAddress: "module.airflow.aws_mwaa_environment.test_airflow_env",
+			ResourceID:      "id_test_airflow_env",
+			SupportsImport:  true,
+			DefaultIDRule:   true,
+			SourceAddress:   "module.test_mwaa.aws_mwaa_environment.test_airflow_env",
+		},
+	}
+	require.Equal(t, expectedImports, actual)
+}
+
+func Test_GenerateImports_ShouldComputeIDsUsingGivenIDRules(t *testing.T) {
+	rulesFile, err := os.Open(filepath.FromSlash("testdata/rules.json"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = rulesFile.Close()
+	})
+	rules, err := tfimportgen.LoadIDRules(rulesFile)
+	require.NoError(t, err)
+	stateJsonFile, err := os.Open(filepath.FromSlash("testdata/only_root_resources.json"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = stateJsonFile.Close()
+	})
+
+	actual, err := tfimportgen.GenerateImports(stateJsonFile, nil, tfimportgen.WithIDRules(rules...))
+
+	require.NoError(t, err)
+	expectedImports := tfimportgen.TerraformImports{
+		{
+			ResourceAddress: "aws_glue_catalog_database.test_db",
+			ResourceID:      "123456789012:id_test_db",
+			SupportsImport:  true,
+		},
+		{
+			ResourceAddress: "aws_iam_instance_profile.test_instance_profile",
+			ResourceID:      "id_test_instance_profile",
+			SupportsImport:  false,
+		},
+	}
+	require.Equal(t, expectedImports, actual)
+}
+
+func Test_GenerateImports_ShouldRejectInvalidAddresses(t *testing.T) {
+	stateJsonFile, err := os.Open(filepath.FromSlash("testdata/resources_in_root_and_child_modules.json"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = stateJsonFile.Close()
+	})
+
+	_, err = tfimportgen.GenerateImports(stateJsonFile, []string{"module.test_mwaa.aws_iam_policy"})
+
+	require.EqualError(t, err, `invalid address "module.test_mwaa.aws_iam_policy": expected module path followed by resource type and name`)
+}
+
+func Test_GenerateImports_ShouldGenerateImportsForResourcesMatchingPatterns(t *testing.T) {
+	tests := []struct {
+		name     string
+		address  []string
+		options  []tfimportgen.Option
+		expected tfimportgen.TerraformImports
+	}{
+		{
+			name:    "glob",
+			address: []string{"module.*.aws_iam_*.*"},
+			expected: tfimportgen.TerraformImports{
+				{
+					ResourceAddress: "module.test_mwaa.aws_iam_policy.test_mwaa_permissions",
+					ResourceID:      "id_test_mwaa_permissions",
+					SupportsImport:  true,
+					DefaultIDRule:   true,
+				},
+			},
+		},
+		{
+			name:    "regex",
+			address: []string{"aws_(glue|iam)_.*", "module.test_.*.aws_mwaa_environment"},
+			options: []tfimportgen.Option{tfimportgen.WithRegexAddresses()},
+			expected: tfimportgen.TerraformImports{
+				{
+					ResourceAddress: "aws_glue_catalog_database.test_db",
+					ResourceID:      "id_test_db",
+					SupportsImport:  true,
+					DefaultIDRule:   true,
+				},
+				{
+					ResourceAddress: "aws_iam_instance_profile.test_instance_profile",
+					ResourceID:      "id_test_instance_profile",
+					SupportsImport:  true,
+					DefaultIDRule:   true,
+				},
+				{
+					ResourceAddress: "module.test_mwaa.aws_mwaa_environment.test_airflow_env",
+					ResourceID:      "id_test_airflow_env",
+					SupportsImport:  true,
+					DefaultIDRule:   true,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stateJsonFile, err := os.Open(filepath.FromSlash("testdata/resources_in_root_and_child_modules.json"))
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				_ = stateJsonFile.Close()
+			})
+
+			actual, err := tfimportgen.GenerateImports(stateJsonFile, tt.address, tt.options...)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func Test_GenerateImports_ShouldExcludeGivenAddressesAndTypes(t *testing.T) {
+	stateJsonFile, err := os.Open(filepath.FromSlash("testdata/resources_in_root_and_child_modules.json"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = stateJsonFile.Close()
+	})
+
+	actual, err := tfimportgen.GenerateImports(stateJsonFile, []string{"module.test_mwaa", "aws_glue_catalog_database.test_db"},
+		tfimportgen.WithExcludedAddresses("aws_glue_catalog_database.test_db"),
+		tfimportgen.WithExcludedTypes("aws_iam_policy"),
+	)
+
+	require.NoError(t, err)
+	expectedImports := tfimportgen.TerraformImports{
+		{
+			ResourceAddress: "module.test_mwaa.aws_mwaa_environment.test_airflow_env",
+			ResourceID:      "id_test_airflow_env",
+			SupportsImport:  true,
+			DefaultIDRule:   true,
+		},
+	}
+	require.Equal(t, expectedImports, actual)
+}
+
+func Test_GenerateImports_ShouldSelectResourcesByProvider(t *testing.T) {
+	tests := []struct {
+		name      string
+		providers []string
+		expected  tfimportgen.TerraformImports
+	}{
+		{
+			name:      "all providers",
+			providers: nil,
+			expected: tfimportgen.TerraformImports{
+				{
+					ResourceAddress: "aws_s3_bucket.logs",
+					ResourceID:      "logs",
+					SupportsImport:  true,
+					DefaultIDRule:   true,
+				},
+				{
+					ResourceAddress: "aws_acm_certificate.cdn",
+					ResourceID:      "arn:aws:acm:us-east-1:123456789012:certificate/cdn",
+					SupportsImport:  true,
+					DefaultIDRule:   true,
+					Provider:        "aws.us_east_1",
+				},
+				{
+					ResourceAddress: "module.replica.aws_s3_bucket.this",
+					ResourceID:      "replica",
+					SupportsImport:  true,
+					DefaultIDRule:   true,
+				},
+				{
+					ResourceAddress: "google_storage_bucket.archive",
+					ResourceID:      "archive",
+					SupportsImport:  true,
+					DefaultIDRule:   true,
+				},
+			},
+		},
+		{
+			name:      "by provider type",
+			providers: []string{"google"},
+			expected: tfimportgen.TerraformImports{
+				{
+					ResourceAddress: "google_storage_bucket.archive",
+					ResourceID:      "archive",
+					SupportsImport:  true,
+					DefaultIDRule:   true,
+				},
+			},
+		},
+		{
+			name:      "by aliased provider configuration",
+			providers: []string{"aws.us_east_1"},
+			expected: tfimportgen.TerraformImports{
+				{
+					ResourceAddress: "aws_acm_certificate.cdn",
+					ResourceID:      "arn:aws:acm:us-east-1:123456789012:certificate/cdn",
+					SupportsImport:  true,
+					DefaultIDRule:   true,
+					Provider:        "aws.us_east_1",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stateFile, err := os.Open(filepath.FromSlash("testdata/state_file_with_aliased_providers.json"))
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				_ = stateFile.Close()
+			})
+
+			actual, err := tfimportgen.GenerateImports(stateFile, nil, tfimportgen.WithProviders(tt.providers...))
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func Test_GenerateImportsFromStates_ShouldMergeImportsOfAllInputs(t *testing.T) {
+	networkStateFile, err := os.Open(filepath.FromSlash("testdata/only_root_resources.json"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = networkStateFile.Close()
+	})
+	appStateFile, err := os.Open(filepath.FromSlash("testdata/state_file_resources_in_root_and_child_modules.json"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = appStateFile.Close()
+	})
+
+	actual, err := tfimportgen.GenerateImportsFromStates([]tfimportgen.StateInput{
+		{Reader: networkStateFile, ModulePrefix: "module.network"},
+		{Reader: appStateFile, ModulePrefix: "module.app"},
+	}, []string{"aws_glue_catalog_database.test_db", "module.test_mwaa.aws_iam_policy.test_mwaa_permissions"})
+
+	require.NoError(t, err)
+	expectedImports := tfimportgen.TerraformImports{
+		{
+			ResourceAddress: "module.network.aws_glue_catalog_database.test_db",
+			ResourceID:      "id_test_db",
+			SupportsImport:  true,
+			DefaultIDRule:   true,
+			SourceAddress:   "aws_glue_catalog_database.test_db",
+		},
+		{
+			ResourceAddress: "module.app.aws_glue_catalog_database.test_db",
+			ResourceID:      "id_test_db",
+			SupportsImport:  true,
+			DefaultIDRule:   true,
+			SourceAddress:   "aws_glue_catalog_database.test_db",
+		},
+		{
+			ResourceAddress: "module.app.module.test_mwaa.aws_iam_policy.test_mwaa_permissions",
+			ResourceID:      "id_test_mwaa_permissions",
+			SupportsImport:  true,
+			DefaultIDRule:   true,
+			SourceAddress:   "module.test_mwaa.aws_iam_policy.test_mwaa_permissions",
+		},
+	}
+	require.Equal(t, expectedImports, actual)
+}
+
+func Test_GenerateImportsFromStates_ShouldRejectInvalidModulePrefix(t *testing.T) {
+	_, err := tfimportgen.GenerateImportsFromStates([]tfimportgen.StateInput{
+		{Reader: bytes.NewBufferString("{}"), ModulePrefix: "aws_s3_bucket.this"},
+	}, nil)
+
+	require.EqualError(t, err, `invalid module prefix "aws_s3_bucket.this", expected a module address like module.example`)
+}
+
+func Test_GenerateImports_ShouldReturnDiagnosticsForIDsWhichCannotBeComputed(t *testing.T) {
+	state := `{"format_version": "1.0", "values": {"root_module": {"resources": [
+		{"address": "aws_iam_role_policy_attachment.test", "mode": "managed", "type": "aws_iam_role_policy_attachment", "name": "test", "values": {"role": "test-role"}},
+		{"address": "aws_iam_role.test", "mode": "managed", "type": "aws_iam_role", "name": "test", "values": {"id": "test-role"}}
+	]}}}`
+
+	imports, err := tfimportgen.GenerateImports(bytes.NewBufferString(state), nil)
+
+	require.NoError(t, err)
+	require.Equal(t, []tfimportgen.Diagnostic{
+		{Kind: tfimportgen.DiagnosticMissingAttribute, Address: "aws_iam_role_policy_attachment.test", Attribute: "policy_arn"},
+	}, imports.Diagnostics())
+}
+
+func Test_GenerateImports_ShouldReturnParseErrorForUnrecognizedInput(t *testing.T) {
+	_, err := tfimportgen.GenerateImports(bytes.NewBufferString(`{"unknown": true}`), nil)
+
+	var parseErr tfimportgen.ParseError
+	require.ErrorAs(t, err, &parseErr)
+}
+
+func Test_GenerateImports_ShouldNotReturnParseErrorWhenTheInputCannotBeRead(t *testing.T) {
+	readErr := errors.New("connection reset")
+
+	_, err := tfimportgen.GenerateImports(iotest.ErrReader(readErr), nil)
+
+	require.ErrorIs(t, err, readErr)
+	var parseErr tfimportgen.ParseError
+	require.False(t, errors.As(err, &parseErr))
+}
+
+func Test_GenerateImports_ShouldCarryResourceIdentity(t *testing.T) {
+	state := `{"version": 4, "resources": [
+		{"mode": "managed", "type": "aws_instance", "name": "web", "instances": [
+			{"attributes": {"id": "i-1"}, "identity_schema_version": 0, "identity": {"id": "i-1", "region": "eu-west-1"}}
+		]}
+	]}`
+
+	imports, err := tfimportgen.GenerateImports(bytes.NewBufferString(state), nil)
+
+	require.NoError(t, err)
+	require.Equal(t, tfimportgen.TerraformImports{
+		{
+			ResourceAddress: "aws_instance.web",
+			ResourceID:      "i-1",
+			SupportsImport:  true,
+			DefaultIDRule:   true,
+			Identity:        map[string]any{"id": "i-1", "region": "eu-west-1"},
+		},
+	}, imports)
+}
+
+func Test_GenerateImports_ShouldRekeyCountResourcesUsingKeyMappings(t *testing.T) {
+	stateJsonFile, err := os.Open(filepath.FromSlash("testdata/counted_resources.json"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = stateJsonFile.Close()
+	})
+
+	actual, err := tfimportgen.GenerateImports(stateJsonFile, nil,
+		tfimportgen.WithKeyMappings(tfimportgen.KeyMapping{Resource: "aws_s3_bucket.this", Attribute: "bucket"}),
+		tfimportgen.WithAddressMappings(tfimportgen.AddressMapping{From: "aws_s3_bucket.this", To: "aws_s3_bucket.buckets"}),
+	)
+
+	require.NoError(t, err)
+	expectedImports := tfimportgen.TerraformImports{
+		{
+			ResourceAddress: `aws_s3_bucket.buckets["example-logs"]`,
+			ResourceID:      "example-logs",
+			SupportsImport:  true,
+			DefaultIDRule:   true,
+			SourceAddress:   "aws_s3_bucket.this[0]",
+		},
+		{
+			ResourceAddress: `aws_s3_bucket.buckets["example-assets"]`,
+			ResourceID:      "example-assets",
+			SupportsImport:  true,
+			DefaultIDRule:   true,
+			SourceAddress:   "aws_s3_bucket.this[1]",
+		},
+		{
+			ResourceAddress: "aws_iam_role.this[0]",
+			ResourceID:      "example-role",
+			SupportsImport:  true,
+			DefaultIDRule:   true,
+		},
+	}
+	require.Equal(t, expectedImports, actual)
+}
+
+func Test_GenerateImports_ShouldReportKeyMappingFailures(t *testing.T) {
+	state := `{"format_version": "1.0", "values": {"root_module": {"resources": [
+		{"address": "aws_iam_role.this[0]", "mode": "managed", "type": "aws_iam_role", "name": "this", "index": 0, "values": {"id": "a", "name": "role", "path": "/", "max_session_duration": 3600}},
+		{"address": "aws_iam_role.this[1]", "mode": "managed", "type": "aws_iam_role", "name": "this", "index": 1, "values": {"id": "b", "name": "role", "path": ""}},
+		{"address": "aws_iam_role.this[2]", "mode": "managed", "type": "aws_iam_role", "name": "this", "index": 2, "values": {"id": "c", "name": "role"}}
+	]}}}`
+	tests := []struct {
+		name          string
+		keyMapping    tfimportgen.KeyMapping
+		expectedError string
+	}{
+		{
+			name:       "key collisions",
+			keyMapping: tfimportgen.KeyMapping{Resource: "aws_iam_role.this", Attribute: "name"},
+			expectedError: `key collision, both aws_iam_role.this[0] and aws_iam_role.this[1] are re-keyed to aws_iam_role.this["role"]
+key collision, both aws_iam_role.this[0] and aws_iam_role.this[2] are re-keyed to aws_iam_role.this["role"]`,
+		},
+		{
+			name:          "empty attribute",
+			keyMapping:    tfimportgen.KeyMapping{Resource: "aws_iam_role.this", Attribute: "path"},
+			expectedError: "cannot re-key aws_iam_role.this[1], its attribute path is empty",
+		},
+		{
+			name:          "attribute which is not a string",
+			keyMapping:    tfimportgen.KeyMapping{Resource: "aws_iam_role.this", Attribute: "max_session_duration"},
+			expectedError: "cannot re-key aws_iam_role.this[0], its attribute max_session_duration is not a string",
+		},
+		{
+			name:          "module instead of resource",
+			keyMapping:    tfimportgen.KeyMapping{Resource: "module.roles", Attribute: "name"},
+			expectedError: "invalid key mapping module.roles=name, expected a resource and an attribute",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tfimportgen.GenerateImports(bytes.NewBufferString(state), nil, tfimportgen.WithKeyMappings(tt.keyMapping))
+
+			require.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
+
+func Test_GenerateImportsFromStates_ShouldReportKeyCollisionsWithOtherInstancesAndInputs(t *testing.T) {
+	rekeyedState := `{"format_version": "1.0", "values": {"root_module": {"resources": [
+		{"address": "aws_iam_role.this[0]", "mode": "managed", "type": "aws_iam_role", "name": "this", "index": 0, "values": {"id": "a", "name": "admin"}},
+		{"address": "aws_iam_role.this[\"admin\"]", "mode": "managed", "type": "aws_iam_role", "name": "this", "index": "admin", "values": {"id": "b", "name": "admin"}}
+	]}}}`
+	otherState := `{"format_version": "1.0", "values": {"root_module": {"resources": [
+		{"address": "aws_iam_role.this[1]", "mode": "managed", "type": "aws_iam_role", "name": "this", "index": 1, "values": {"id": "c", "name": "admin"}}
+	]}}}`
+
+	_, err := tfimportgen.GenerateImportsFromStates([]tfimportgen.StateInput{
+		{Reader: bytes.NewBufferString(rekeyedState)},
+		{Reader: bytes.NewBufferString(otherState)},
+	}, nil, tfimportgen.WithKeyMappings(tfimportgen.KeyMapping{Resource: "aws_iam_role.this", Attribute: "name"}))
+
+	require.EqualError(t, err, `key collision, aws_iam_role.this[0] is re-keyed to aws_iam_role.this["admin"], which aws_iam_role.this["admin"] is imported to as well
+key collision, both aws_iam_role.this[0] and aws_iam_role.this[1] are re-keyed to aws_iam_role.this["admin"]`)
+}
+
+func Test_ParseKeyMapping(t *testing.T) {
+	keyMapping, err := tfimportgen.ParseKeyMapping("module.storage.aws_s3_bucket.this = bucket")
+	require.NoError(t, err)
+	require.Equal(t, tfimportgen.KeyMapping{Resource: "module.storage.aws_s3_bucket.this", Attribute: "bucket"}, keyMapping)
+
+	_, err = tfimportgen.ParseKeyMapping("aws_s3_bucket.this")
+	require.EqualError(t, err, `invalid key mapping "aws_s3_bucket.this", expected the form resource=attribute`)
+}
@@ -0,0 +1,130 @@
+package tfimportgen
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/kishaningithub/tf-import-gen/pkg/internal/parser"
+)
+
+// DestinationPlan is the plan of the destination code base after adding the
+// generated imports, as printed by `terraform show -json`
+type DestinationPlan struct {
+	// resourceChanges are the changes by their normalized resource address
+	resourceChanges map[string]*tfjson.ResourceChange
+}
+
+// LoadDestinationPlan reads the output of `terraform show -json` for a plan
+func LoadDestinationPlan(reader io.Reader) (DestinationPlan, error) {
+	var plan tfjson.Plan
+	err := json.NewDecoder(reader).Decode(&plan)
+	if err != nil {
+		return DestinationPlan{}, fmt.Errorf("invalid destination plan: %w", err)
+	}
+	if plan.PlannedValues == nil {
+		return DestinationPlan{}, errors.New("invalid destination plan: expected the output of terraform show -json for a plan")
+	}
+	destinationPlan := DestinationPlan{resourceChanges: make(map[string]*tfjson.ResourceChange)}
+	for _, resourceChange := range plan.ResourceChanges {
+		destinationPlan.resourceChanges[normalizeAddress(resourceChange.Address)] = resourceChange
+	}
+	return destinationPlan, nil
+}
+
+// normalizeAddress formats the address the way tf-import-gen does, so that
+// addresses from terraform can be compared with the generated ones
+func normalizeAddress(address string) string {
+	parsedAddress, err := parser.ParseAddress(address)
+	if err != nil {
+		return address
+	}
+	return parsedAddress.String()
+}
+
+// VerificationIssueKind is the kind of problem the destination plan reveals
+// about an import
+type VerificationIssueKind string
+
+const (
+	// VerificationMissingImport is reported when the plan does not import the
+	// resource, e.g. because the import block was not added or the resource
+	// already is in the state
+	VerificationMissingImport VerificationIssueKind = "missing_import"
+	// VerificationUpdateAfterImport is reported when the plan updates the
+	// resource in place after importing it, i.e. the configuration does not
+	// match the imported resource
+	VerificationUpdateAfterImport VerificationIssueKind = "update_after_import"
+	// VerificationReplaceAfterImport is reported when the plan replaces the
+	// resource after importing it
+	VerificationReplaceAfterImport VerificationIssueKind = "replace_after_import"
+	// VerificationNotInConfig is reported when the import target has no
+	// resource block, so that terraform generates its configuration
+	VerificationNotInConfig VerificationIssueKind = "not_in_config"
+	// VerificationIDMismatch is reported when the plan imports the resource
+	// using another id, or another identity, than the generated one
+	VerificationIDMismatch VerificationIssueKind = "id_mismatch"
+)
+
+// VerificationIssue is a problem the destination plan reveals about an import
+type VerificationIssue struct {
+	Kind VerificationIssueKind `json:"kind"`
+	// Address is the address the resource is imported to
+	Address string `json:"address"`
+}
+
+func (issue VerificationIssue) String() string {
+	switch issue.Kind {
+	case VerificationMissingImport:
+		return fmt.Sprintf("%s is not imported by the plan", issue.Address)
+	case VerificationUpdateAfterImport:
+		return fmt.Sprintf("%s would be updated in place after the import", issue.Address)
+	case VerificationReplaceAfterImport:
+		return fmt.Sprintf("%s would be replaced after the import", issue.Address)
+	case VerificationIDMismatch:
+		return fmt.Sprintf("%s is imported by the plan using another id or identity than the generated one", issue.Address)
+	default:
+		return fmt.Sprintf("%s is not in the configuration, its configuration would be generated", issue.Address)
+	}
+}
+
+// Verify cross-checks the imports against the resources the plan imports.
+// Resources which do not support import are skipped.
+func (plan DestinationPlan) Verify(imports TerraformImports) []VerificationIssue {
+	var issues []VerificationIssue
+	for _, terraformImport := range imports {
+		if !terraformImport.SupportsImport {
+			continue
+		}
+		resourceChange, ok := plan.resourceChanges[normalizeAddress(terraformImport.ResourceAddress)]
+		if !ok || resourceChange.Change == nil || resourceChange.Change.Importing == nil {
+			issues = append(issues, VerificationIssue{Kind: VerificationMissingImport, Address: terraformImport.ResourceAddress})
+			continue
+		}
+		if !importsSameResource(resourceChange.Change.Importing, terraformImport) {
+			issues = append(issues, VerificationIssue{Kind: VerificationIDMismatch, Address: terraformImport.ResourceAddress})
+		}
+		if len(resourceChange.Change.GeneratedConfig) > 0 {
+			issues = append(issues, VerificationIssue{Kind: VerificationNotInConfig, Address: terraformImport.ResourceAddress})
+		}
+		switch {
+		case resourceChange.Change.Actions.Replace():
+			issues = append(issues, VerificationIssue{Kind: VerificationReplaceAfterImport, Address: terraformImport.ResourceAddress})
+		case resourceChange.Change.Actions.Update():
+			issues = append(issues, VerificationIssue{Kind: VerificationUpdateAfterImport, Address: terraformImport.ResourceAddress})
+		}
+	}
+	return issues
+}
+
+// importsSameResource compares the identity when the plan imports the resource
+// by its identity, and the id otherwise
+func importsSameResource(importing *tfjson.Importing, terraformImport TerraformImport) bool {
+	if importing.Identity != nil {
+		return reflect.DeepEqual(importing.Identity, any(terraformImport.Identity))
+	}
+	return importing.ID == terraformImport.ResourceID
+}
@@ -0,0 +1,130 @@
+package tfimportgen_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	tfimportgen "github.com/kishaningithub/tf-import-gen/pkg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDestinationPlan_Verify(t *testing.T) {
+	planFile, err := os.Open(filepath.FromSlash("testdata/destination_plan.json"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = planFile.Close()
+	})
+	plan, err := tfimportgen.LoadDestinationPlan(planFile)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		address  string
+		id       string
+		identity map[string]any
+		expected []tfimportgen.VerificationIssue
+	}{
+		{name: "imported without changes", address: "aws_s3_bucket.logs", id: "example-logs"},
+		{
+			name:     "imported using another id",
+			address:  "aws_s3_bucket.logs",
+			id:       "other-logs",
+			expected: []tfimportgen.VerificationIssue{{Kind: tfimportgen.VerificationIDMismatch, Address: "aws_s3_bucket.logs"}},
+		},
+		{
+			name:     "imported using the identity",
+			address:  "aws_instance.api",
+			id:       "i-9876543210",
+			identity: map[string]any{"id": "i-9876543210", "region": "eu-west-1"},
+		},
+		{
+			name:     "imported using another identity",
+			address:  "aws_instance.api",
+			id:       "i-9876543210",
+			identity: map[string]any{"id": "i-9876543210", "region": "us-east-1"},
+			expected: []tfimportgen.VerificationIssue{{Kind: tfimportgen.VerificationIDMismatch, Address: "aws_instance.api"}},
+		},
+		{
+			name:     "updated after import",
+			address:  `aws_s3_bucket.this["assets"]`,
+			id:       "example-assets",
+			expected: []tfimportgen.VerificationIssue{{Kind: tfimportgen.VerificationUpdateAfterImport, Address: `aws_s3_bucket.this["assets"]`}},
+		},
+		{
+			name:     "replaced after import",
+			address:  "aws_instance.web[0]",
+			id:       "i-0123456789",
+			expected: []tfimportgen.VerificationIssue{{Kind: tfimportgen.VerificationReplaceAfterImport, Address: "aws_instance.web[0]"}},
+		},
+		{
+			name:     "not in config",
+			address:  "aws_iam_role.deployer",
+			id:       "deployer",
+			expected: []tfimportgen.VerificationIssue{{Kind: tfimportgen.VerificationNotInConfig, Address: "aws_iam_role.deployer"}},
+		},
+		{
+			name:     "resource change without import",
+			address:  "aws_vpc.main",
+			id:       "vpc-0123456789",
+			expected: []tfimportgen.VerificationIssue{{Kind: tfimportgen.VerificationMissingImport, Address: "aws_vpc.main"}},
+		},
+		{
+			name:     "no resource change",
+			address:  "aws_s3_bucket.backups",
+			id:       "example-backups",
+			expected: []tfimportgen.VerificationIssue{{Kind: tfimportgen.VerificationMissingImport, Address: "aws_s3_bucket.backups"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imports := tfimportgen.TerraformImports{{ResourceAddress: tt.address, ResourceID: tt.id, SupportsImport: true, Identity: tt.identity}}
+			require.Equal(t, tt.expected, plan.Verify(imports))
+		})
+	}
+}
+
+func TestDestinationPlan_VerifyShouldSkipResourcesWhichDoNotSupportImport(t *testing.T) {
+	plan, err := tfimportgen.LoadDestinationPlan(bytes.NewBufferString(`{"format_version": "1.2", "planned_values": {"root_module": {}}}`))
+	require.NoError(t, err)
+
+	imports := tfimportgen.TerraformImports{{ResourceAddress: "aws_iam_policy_attachment.test", ResourceID: "test"}}
+
+	require.Empty(t, plan.Verify(imports))
+}
+
+func TestLoadDestinationPlan_ShouldFailForInvalidPlans(t *testing.T) {
+	tests := []struct {
+		name          string
+		plan          string
+		expectedError string
+	}{
+		{name: "invalid json", plan: "{", expectedError: "invalid destination plan: unexpected EOF"},
+		{name: "state instead of plan", plan: `{"format_version": "1.0", "values": {"root_module": {}}}`, expectedError: "invalid destination plan: expected the output of terraform show -json for a plan"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tfimportgen.LoadDestinationPlan(bytes.NewBufferString(tt.plan))
+			require.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestVerificationIssue_String(t *testing.T) {
+	tests := []struct {
+		issue    tfimportgen.VerificationIssue
+		expected string
+	}{
+		{issue: tfimportgen.VerificationIssue{Kind: tfimportgen.VerificationMissingImport, Address: "aws_vpc.main"}, expected: "aws_vpc.main is not imported by the plan"},
+		{issue: tfimportgen.VerificationIssue{Kind: tfimportgen.VerificationUpdateAfterImport, Address: "aws_vpc.main"}, expected: "aws_vpc.main would be updated in place after the import"},
+		{issue: tfimportgen.VerificationIssue{Kind: tfimportgen.VerificationReplaceAfterImport, Address: "aws_vpc.main"}, expected: "aws_vpc.main would be replaced after the import"},
+		{issue: tfimportgen.VerificationIssue{Kind: tfimportgen.VerificationIDMismatch, Address: "aws_vpc.main"}, expected: "aws_vpc.main is imported by the plan using another id or identity than the generated one"},
+		{issue: tfimportgen.VerificationIssue{Kind: tfimportgen.VerificationNotInConfig, Address: "aws_vpc.main"}, expected: "aws_vpc.main is not in the configuration, its configuration would be generated"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.issue.Kind), func(t *testing.T) {
+			require.Equal(t, tt.expected, tt.issue.String())
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kishaningithub/tf-import-gen/pkg"
+	"github.com/spf13/cobra"
+)
+
+func newVerifyCommand() *cobra.Command {
+	var planFile string
+	var selection selectionFlags
+	var importTargets importFlags
+	verifyCmd := &cobra.Command{
+		Use:   "verify [flags] address...",
+		Short: "Verify the imports using the plan of the destination code base",
+		Long: strings.TrimSpace(`
+Verify the imports using the plan of the destination code base.
+
+The imports are generated from the source state like the main command does, so
+the addresses and the flags deciding the import targets have to be the same.
+They are cross-checked against the resources the destination plan imports, and
+every import is reported which
+  is not imported by the plan
+  would be updated in place or replaced after the import
+  is imported by the plan using another id or identity
+  targets a resource which is not in the configuration
+
+The exit code is 7 when any import is reported.
+`),
+		Example: `
+## Verifying the imports of a module which is renamed in the destination code base
+terraform -chdir=../destination plan -out plan.tfplan
+terraform -chdir=../destination show -json plan.tfplan > plan.json
+terraform show -json | tf-import-gen verify --plan plan.json --map module.old=module.new module.old
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			addresses := []string{""}
+			if len(args) > 0 {
+				addresses = args
+			}
+			importOptions, err := importTargets.options()
+			if err != nil {
+				return err
+			}
+			options := append(selection.options(), importOptions...)
+			plan, err := loadDestinationPlan(planFile)
+			if err != nil {
+				return err
+			}
+			inputs, err := openStateInputs(selection.stateInputs)
+			if err != nil {
+				return err
+			}
+			defer closeStateInputs(inputs)
+			imports, err := tfimportgen.GenerateImportsFromStates(inputs, addresses, options...)
+			if err != nil {
+				return err
+			}
+			issues := plan.Verify(imports)
+			for _, issue := range issues {
+				fmt.Println(issue)
+			}
+			if len(issues) > 0 {
+				return exitError{code: exitCodeVerificationIssues, err: fmt.Errorf("verification failed: %d issues found", len(issues))}
+			}
+			fmt.Println("all the imports are in the plan without changes after the import")
+			return nil
+		},
+	}
+	selection.register(verifyCmd.Flags())
+	importTargets.register(verifyCmd.Flags())
+	verifyCmd.Flags().StringVar(&planFile, "plan", "", "output of terraform show -json for the plan of the destination code base")
+	_ = verifyCmd.MarkFlagRequired("plan")
+	return verifyCmd
+}